@@ -0,0 +1,203 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	anthropicBaseURL      = "https://api.anthropic.com/v1"
+	anthropicAPIVersion   = "2023-06-01"
+	anthropicDefaultModel = "claude-sonnet-4-5"
+	anthropicMaxTokens    = 4096
+)
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Stream      bool               `json:"stream,omitempty"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	TopP        *float64           `json:"top_p,omitempty"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+type anthropicStreamEvent struct {
+	Type string `json:"type"`
+	// Message is set on message_start, with the input token count
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	// Usage is set on message_delta, with the output token count
+	Usage anthropicUsage `json:"usage"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type anthropicModelList struct {
+	Data []struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"data"`
+}
+
+// anthropicHeader returns the authentication headers for the Anthropic API.
+// It requires ANTHROPIC_API_KEY to be set.
+func anthropicHeader() (http.Header, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+	}
+
+	header := http.Header{}
+	header.Set("x-api-key", apiKey)
+	header.Set("anthropic-version", anthropicAPIVersion)
+	return header, nil
+}
+
+// anthropicModels lists the models available to the API key.
+func anthropicModels(ctx context.Context) ([]Model, error) {
+	header, err := anthropicHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := apiURL(Anthropic, "", "/models")
+	if err != nil {
+		return nil, err
+	}
+
+	var list anthropicModelList
+	if err := getJSON(ctx, "anthropic", endpoint, header, &list); err != nil {
+		return nil, err
+	}
+
+	models := make([]Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, Model{ID: m.ID, Provider: Anthropic, Description: m.DisplayName})
+	}
+	return models, nil
+}
+
+// askAnthropic sends a prompt to the Anthropic Messages API.
+// It requires ANTHROPIC_API_KEY to be set.
+func askAnthropic(ctx context.Context, req Request) (Response, error) {
+	header, err := anthropicHeader()
+	if err != nil {
+		return Response{}, err
+	}
+
+	endpoint, err := apiURL(Anthropic, req.BaseURL, "/messages")
+	if err != nil {
+		return Response{}, err
+	}
+
+	model := req.Model
+	maxTokens := anthropicMaxTokens
+	if req.MaxOutputTokens > 0 {
+		maxTokens = req.MaxOutputTokens
+	}
+
+	resp, err := postJSON(ctx, "anthropic", endpoint, header, anthropicRequest{
+		Model:       model,
+		MaxTokens:   maxTokens,
+		System:      req.System,
+		Messages:    []anthropicMessage{{Role: "user", Content: req.Prompt}},
+		Stream:      req.Stream != nil,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	if req.Stream != nil {
+		return streamAnthropic(resp.Body, req.Stream, model)
+	}
+
+	var parsed anthropicResponse
+	if err := decodeJSON("anthropic", resp.Body, &parsed); err != nil {
+		return Response{}, err
+	}
+
+	var text strings.Builder
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	return Response{
+		Text:  strings.TrimSpace(text.String()),
+		Model: model,
+		Usage: Usage{InputTokens: parsed.Usage.InputTokens, OutputTokens: parsed.Usage.OutputTokens},
+	}, nil
+}
+
+// streamAnthropic consumes a Messages API event stream, copying text deltas
+// to w as they arrive.
+func streamAnthropic(r io.Reader, w io.Writer, model string) (Response, error) {
+	var text strings.Builder
+	var usage Usage
+	err := readSSE(r, func(_, data string) error {
+		var ev anthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			return fmt.Errorf("anthropic: decode stream event: %w", err)
+		}
+		switch ev.Type {
+		case "message_start":
+			usage.InputTokens = ev.Message.Usage.InputTokens
+		case "message_delta":
+			usage.OutputTokens = ev.Usage.OutputTokens
+		case "content_block_delta":
+			if ev.Delta.Type == "text_delta" {
+				text.WriteString(ev.Delta.Text)
+				_, err := io.WriteString(w, ev.Delta.Text)
+				return err
+			}
+		case "error":
+			return fmt.Errorf("anthropic API error (%s): %s", ev.Error.Type, ev.Error.Message)
+		case "message_stop":
+			return io.EOF
+		}
+		return nil
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{Text: strings.TrimSpace(text.String()), Model: model, Usage: usage}, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourorg/arc-ai/internal/fileutil"
+)
+
+// cacheEntry is a cached AI response.
+type cacheEntry struct {
+	Provider string    `json:"provider"`
+	Model    string    `json:"model"`
+	Response string    `json:"response"`
+	Time     time.Time `json:"time"`
+}
+
+// cacheKey hashes the inputs that determine a response. baseURL is the
+// provider's endpoint if it is not the usual one.
+func cacheKey(provider string, req Request) string {
+	parts := []string{provider, req.Model, req.System, req.Prompt}
+	// Only when set, so the keys of other requests stay the same
+	if len(req.Schema) > 0 {
+		parts = append(parts, string(req.Schema))
+	}
+	if base := customBaseURL(provider, req.BaseURL); base != "" {
+		parts = append(parts, "base-url:"+base)
+	}
+	if req.MaxOutputTokens > 0 {
+		parts = append(parts, fmt.Sprintf("max-output-tokens:%d", req.MaxOutputTokens))
+	}
+	if req.Temperature != nil {
+		parts = append(parts, fmt.Sprintf("temperature:%g", *req.Temperature))
+	}
+	if req.TopP != nil {
+		parts = append(parts, fmt.Sprintf("top-p:%g", *req.TopP))
+	}
+
+	h := sha256.New()
+	// NUL separators keep ("ab", "c") and ("a", "bc") distinct
+	for _, s := range parts {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Client) cachePath(key string) string {
+	return filepath.Join(c.CacheDir, "responses", key+".json")
+}
+
+// cacheGet returns the cached response for key if it is younger than ttl.
+// Missing, stale, and unreadable entries are all misses.
+func (c *Client) cacheGet(key string, ttl time.Duration) (string, bool) {
+	data, err := os.ReadFile(c.cachePath(key))
+	if err != nil {
+		return "", false
+	}
+
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return "", false
+	}
+	if time.Since(e.Time) > ttl {
+		return "", false
+	}
+	return e.Response, true
+}
+
+// cachePut stores a response under key.
+func (c *Client) cachePut(key string, e cacheEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+	if err := fileutil.WriteAtomic(c.cachePath(key), data); err != nil {
+		return fmt.Errorf("write cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// cliWaitDelay bounds how long a cancelled CLI provider may take to exit.
+const cliWaitDelay = time.Second
+
+// maxArgPrompt is the prompt size above which CLI providers get the prompt
+// on stdin rather than as an argument.
+const maxArgPrompt = 8 * 1024
+
+func askClaude(ctx context.Context, req Request) (Response, error) {
+	args := []string{"--print"}
+	if req.Model != "" {
+		args = append(args, "--model", req.Model)
+	}
+	args, stdin := promptArgs(args, cliPrompt(req))
+
+	text, err := runCLI(ctx, "claude", args, stdin, req.Stream)
+	return Response{Text: text, Model: req.Model}, err
+}
+
+func askCodex(ctx context.Context, req Request) (Response, error) {
+	args := []string{"ask"}
+	if req.Model != "" {
+		args = append(args, "--model", req.Model)
+	}
+	args, stdin := promptArgs(args, cliPrompt(req))
+
+	text, err := runCLI(ctx, "codex", args, stdin, req.Stream)
+	return Response{Text: text, Model: req.Model}, err
+}
+
+// cliPrompt returns the prompt for a CLI provider, which has no separate
+// system message or length limit, with any system prompt placed ahead of it
+// and any limit asked for after it.
+func cliPrompt(req Request) string {
+	prompt := req.Prompt
+	if req.MaxOutputTokens > 0 {
+		// About three quarters of a word per token
+		prompt += fmt.Sprintf("\n\nKeep your response under %d tokens (about %d words).",
+			req.MaxOutputTokens, max(1, req.MaxOutputTokens*3/4))
+	}
+	if req.System == "" {
+		return prompt
+	}
+	return fmt.Sprintf("System instructions:\n%s\n\n%s", req.System, prompt)
+}
+
+// promptArgs appends a short prompt to args. A prompt of maxArgPrompt bytes
+// or more is returned as stdin instead, since large diffs can exceed the
+// argument length limit (E2BIG); the CLIs read the prompt from stdin when
+// none is given as an argument.
+func promptArgs(args []string, prompt string) ([]string, string) {
+	if len(prompt) >= maxArgPrompt {
+		return args, prompt
+	}
+	return append(args, prompt), ""
+}
+
+// runCLI runs a provider CLI and returns its trimmed stdout. A non-empty
+// stdin is written to the process's standard input. If stream is non-nil,
+// stdout is also copied to it as the process writes.
+func runCLI(ctx context.Context, name string, args []string, stdin string, stream io.Writer) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		// exec copies the reader in its own goroutine, so a CLI that writes
+		// output before reading all of its input cannot deadlock
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	killProcessGroup(cmd)
+	// Don't wait on output pipes held open by grandchildren once killed
+	cmd.WaitDelay = cliWaitDelay
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stream != nil {
+		cmd.Stdout = io.MultiWriter(&stdout, stream)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", &cliError{Name: name, Err: err, Stderr: strings.TrimSpace(stderr.String())}
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// cliError is a failed run of a provider CLI.
+type cliError struct {
+	Name   string
+	Err    error
+	Stderr string
+}
+
+func (e *cliError) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("%s failed: %v: %s", e.Name, e.Err, e.Stderr)
+	}
+	return fmt.Sprintf("%s failed: %v", e.Name, e.Err)
+}
+
+func (e *cliError) Unwrap() error { return e.Err }
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// largePrompt is over 1 MiB, well past Linux's 128 KiB limit on a single
+// argument and many systems' limit on all of them.
+var largePrompt = strings.Repeat("0123456789abcdef", 1<<16) + "end"
+
+func TestPromptArgs(t *testing.T) {
+	short := "Explain this."
+	args, stdin := promptArgs([]string{"--print"}, short)
+	if len(args) != 2 || args[1] != short || stdin != "" {
+		t.Errorf("short prompt: args %q, stdin %q; want it as the last argument", args, stdin)
+	}
+
+	long := strings.Repeat("x", maxArgPrompt)
+	args, stdin = promptArgs([]string{"--print"}, long)
+	if len(args) != 1 || stdin != long {
+		t.Errorf("prompt of %d bytes: args %q, stdin of %d bytes; want it on stdin", len(long), args, len(stdin))
+	}
+}
+
+// TestAskClaudeLargePrompt runs a stand-in claude CLI that prints its
+// prompt, from its last argument or else from stdin.
+func TestAskClaudeLargePrompt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\nif [ $# -gt 1 ]; then printf '%s' \"$2\"; else cat; fi\n"
+	if err := os.WriteFile(filepath.Join(dir, "claude"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	for _, prompt := range []string{"a short prompt", largePrompt} {
+		resp, err := askClaude(context.Background(), Request{Prompt: prompt})
+		if err != nil {
+			t.Fatalf("prompt of %d bytes: %v", len(prompt), err)
+		}
+		if resp.Text != prompt {
+			t.Errorf("prompt of %d bytes came back as %d bytes", len(prompt), len(resp.Text))
+		}
+	}
+}
+
+func TestCommandProviderLargePrompt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs cat")
+	}
+	if err := RegisterCommand("test-cat", CommandProvider{Command: "cat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var c Client
+	resp, err := c.Ask(context.Background(), Request{Prompt: largePrompt, Provider: "test-cat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != largePrompt {
+		t.Errorf("prompt of %d bytes came back as %d bytes", len(largePrompt), len(resp.Text))
+	}
+	if resp.Provider != "test-cat" {
+		t.Errorf("answered by %q, want test-cat", resp.Provider)
+	}
+}
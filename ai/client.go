@@ -0,0 +1,464 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package ai sends prompts to AI providers: the claude and codex CLIs, the
+// Anthropic, OpenAI, and Gemini APIs, a local Ollama server, and commands
+// registered with RegisterCommand.
+//
+//	var c ai.Client
+//	resp, err := c.Ask(ctx, ai.Request{Prompt: "What is a goroutine?"})
+//
+// API providers read their keys from ANTHROPIC_API_KEY, OPENAI_API_KEY, and
+// GEMINI_API_KEY, and Ollama is found through OLLAMA_HOST.
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client sends requests to AI providers. The zero value is ready to use,
+// with no response cache, no logging, and no middleware.
+type Client struct {
+	// CacheDir is where responses are cached for Request.CacheTTL; empty
+	// disables the cache.
+	CacheDir string
+	// Log receives diagnostics, with the provider, model, and duration of
+	// each request as attributes; nil discards them.
+	Log *slog.Logger
+	// Middleware wraps every call to Ask, the first entry outermost.
+	Middleware []Middleware
+}
+
+// Handler answers a Request, as Client.Ask does.
+type Handler func(ctx context.Context, req Request) (Response, error)
+
+// Middleware wraps a Handler to inspect or change requests and responses,
+// for logging, redaction, or metrics. It may change the request before
+// calling next, change the response or error after, or not call next at
+// all. Middleware sees each Ask once, whichever providers are tried.
+type Middleware func(next Handler) Handler
+
+// OnRequest returns a Middleware that calls fn with each request before it
+// is sent. fn may modify the request, for example to redact the prompt, or
+// return an error to stop it being sent.
+func OnRequest(fn func(ctx context.Context, req *Request) error) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (Response, error) {
+			if err := fn(ctx, &req); err != nil {
+				return Response{}, err
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// OnResponse returns a Middleware that calls fn with each request and its
+// outcome. fn may modify the response; the error it returns replaces the
+// request's error, so returning err unchanged keeps it.
+func OnResponse(fn func(ctx context.Context, req Request, resp *Response, err error) error) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (Response, error) {
+			resp, err := next(ctx, req)
+			err = fn(ctx, req, &resp, err)
+			return resp, err
+		}
+	}
+}
+
+// Request describes a single prompt sent to a provider.
+type Request struct {
+	Prompt string
+	// Model is passed to whichever provider answers; empty means its entry
+	// in Models, or else the provider's default (see DefaultModel).
+	Model string
+	// Models are default models by provider name, for a request that may
+	// be answered by more than one provider.
+	Models map[string]string
+	// Aliases map short model names to model IDs (see ResolveModel).
+	Aliases map[string]string
+	// LargerModels map a model to one with a larger context window, which
+	// the request is sent to instead if its prompt is too long for the
+	// first. Models can be aliases. Without one, such a request fails with
+	// ErrContextLength.
+	LargerModels map[string]string
+	// Provider is one of the provider names, or Auto (or empty) to use the
+	// first available provider in Order.
+	Provider string
+	// Order is the fallback order for the auto provider; empty means
+	// Providers order.
+	Order []string
+	// LocalOnly restricts the request to providers that run on this
+	// machine (see Local): the auto provider skips hosted ones, and naming
+	// one in Provider is an error.
+	LocalOnly bool
+	// BaseURL, if set, replaces the endpoint of the provider's API, as
+	// SetBaseURL does for every request. Only the anthropic, openai, and
+	// gemini providers have one.
+	BaseURL string
+	// Race sends the request to every available provider at once with the
+	// auto provider, and uses the first successful response.
+	Race bool
+	// System, if set, steers the assistant's behavior. HTTP providers send
+	// it as the system message; CLI providers get it ahead of the prompt.
+	System string
+	// MaxOutputTokens, if positive, caps the length of the response. HTTP
+	// providers enforce it; CLI providers are asked for it in the prompt.
+	MaxOutputTokens int
+	// Temperature and TopP, if set, control sampling: lower values give
+	// more predictable responses. HTTP providers send them; CLI providers
+	// have no such settings and ignore them.
+	Temperature *float64
+	TopP        *float64
+	// Schema, if set, is a JSON Schema the response should conform to.
+	// OpenAI and Ollama enforce it themselves; other providers are asked
+	// for it in the prompt. The response is not validated.
+	Schema json.RawMessage
+	// Stream, if non-nil, receives the response text as it arrives.
+	// The full response is still returned once the provider finishes.
+	Stream io.Writer
+	// Retries is the number of times a transient failure is retried.
+	Retries int
+	// Timeout bounds the whole request, including retries; 0 means no limit.
+	Timeout time.Duration
+	// CacheTTL reuses a cached response younger than this for the same
+	// provider, model, and prompt; 0 disables the cache.
+	CacheTTL time.Duration
+}
+
+// Response is a provider's answer to a Request.
+type Response struct {
+	Text string
+	// Provider is the provider that answered.
+	Provider string
+	// Model is the model that answered, or "" for a CLI provider's default.
+	Model string
+	Usage Usage
+	// Cached is set when Text came from the response cache.
+	Cached bool
+}
+
+// Usage counts the tokens in a request and its response.
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+	// Estimated is set when the provider did not report usage and the
+	// counts come from EstimateTokens.
+	Estimated bool
+}
+
+// EstimateTokens approximates the number of tokens in s. Most tokenizers
+// average roughly four bytes per token for code and English text.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// discard is the logger of a Client without one.
+var discard = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+
+func (c *Client) log() *slog.Logger {
+	if c.Log != nil {
+		return c.Log
+	}
+	return discard
+}
+
+// Ask sends req to a provider and returns its response. With the auto
+// provider it uses the first available provider in order, or races them
+// with req.Race; otherwise it uses the named provider or fails if it is
+// unavailable. The request passes through c.Middleware on the way.
+func (c *Client) Ask(ctx context.Context, req Request) (Response, error) {
+	h := c.send
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		h = c.Middleware[i](h)
+	}
+	return h(ctx, req)
+}
+
+// Prepare returns req as Ask would send it, without sending it: with
+// Provider set to the provider chosen, Model to the model resolved for it
+// ("" if the provider chooses), and the schema added to the prompt for a
+// provider that cannot enforce it. With req.Race, the provider is the
+// first of those that would be raced.
+func Prepare(req Request) (Request, error) {
+	p, err := selectProvider(req.Provider, req.Order, req.LocalOnly)
+	if err != nil {
+		return Request{}, err
+	}
+	req = prepare(p, req)
+	req.Provider = p.name
+	return req, nil
+}
+
+// prepare resolves req's model for p and adds the schema to its prompt if p
+// cannot enforce it.
+func prepare(p *provider, req Request) Request {
+	req.Model = requestModel(p, req)
+	if len(req.Schema) > 0 && !p.schema {
+		req.Prompt = schemaPrompt(req.Prompt, req.Schema)
+	}
+	return req
+}
+
+// send is the Handler at the bottom of the middleware chain.
+func (c *Client) send(ctx context.Context, req Request) (Response, error) {
+	if req.Race && (req.Provider == "" || req.Provider == Auto) {
+		available, err := availableProviders(req.Order, req.LocalOnly)
+		if err != nil {
+			return Response{}, err
+		}
+		if len(available) > 1 {
+			return c.race(ctx, available, req)
+		}
+	}
+
+	p, err := selectProvider(req.Provider, req.Order, req.LocalOnly)
+	if err != nil {
+		return Response{}, err
+	}
+	return c.ask(ctx, p, req)
+}
+
+// ask sends req to p, as askModel does, moving on to the model that
+// req.LargerModels names while the prompt is too long for the model.
+func (c *Client) ask(ctx context.Context, p *provider, req Request) (Response, error) {
+	tried := map[string]bool{}
+	for {
+		resp, err := c.askModel(ctx, p, req)
+		if err == nil || !errors.Is(err, ErrContextLength) {
+			return resp, err
+		}
+
+		model := requestModel(p, req)
+		tried[model] = true
+		larger := largerModel(p, model, req)
+		if larger == "" {
+			return Response{}, err
+		}
+		if tried[larger] {
+			return Response{}, &Error{
+				Kind:     KindProviderFailed,
+				Msg:      fmt.Sprintf("prompt is too long for %s and every larger model configured for it", model),
+				Provider: p.name,
+				Err:      ErrContextLength,
+			}
+		}
+		c.log().Warn("prompt too long for the model; trying a larger one", "provider", p.name, "model", model, "larger", larger)
+		req.Model = larger
+	}
+}
+
+// largerModel returns the model req.LargerModels names for model, with
+// aliases resolved on both sides, or "" if there is none.
+func largerModel(p *provider, model string, req Request) string {
+	if larger, ok := req.LargerModels[model]; ok {
+		return ResolveModel(p.name, larger, req.Aliases)
+	}
+	for from, larger := range req.LargerModels {
+		if ResolveModel(p.name, from, req.Aliases) == model {
+			return ResolveModel(p.name, larger, req.Aliases)
+		}
+	}
+	return ""
+}
+
+// askModel sends req to p, using the cache and retrying transient
+// failures as the request allows.
+func (c *Client) askModel(ctx context.Context, p *provider, req Request) (Response, error) {
+	req = prepare(p, req)
+	model := req.Model
+	if model == "" {
+		model = "(provider default)"
+	}
+	c.log().Debug("sending request", "provider", p.name, "model", model,
+		"prompt_bytes", len(req.Prompt), "prompt_tokens", EstimateTokens(req.Prompt))
+
+	var key string
+	if req.CacheTTL > 0 && c.CacheDir != "" {
+		key = cacheKey(p.name, req)
+		if text, ok := c.cacheGet(key, req.CacheTTL); ok {
+			c.log().Debug("cache hit", "provider", p.name, "key", key[:12])
+			if req.Stream != nil {
+				io.WriteString(req.Stream, text)
+			}
+			resp := Response{Text: text, Model: req.Model, Cached: true}
+			return c.finish(p, req, resp), nil
+		}
+	}
+
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+
+	// A partially streamed response cannot be taken back, so only retry
+	// while nothing has been written.
+	var stream *countingWriter
+	if req.Stream != nil {
+		stream = &countingWriter{w: req.Stream}
+		req.Stream = stream
+	}
+
+	start := time.Now()
+	attempts, empty := 0, 0
+	var resp Response
+	err := defaultBackoff.retry(ctx, req.Retries, func() error {
+		attempts++
+		var err error
+		resp, err = p.ask(ctx, req)
+		if err == nil && strings.TrimSpace(resp.Text) == "" {
+			// A CLI can exit 0 without printing anything; that is worth one
+			// more try, but not every retry
+			err = ErrEmptyResponse
+			if empty++; empty > 1 {
+				err = permanent(err)
+			}
+		}
+		if err != nil {
+			c.log().Debug("attempt failed", "provider", p.name, "attempt", attempts,
+				"duration", time.Since(start).Round(time.Millisecond), "error", err)
+		}
+		if stream != nil && stream.n > 0 {
+			return permanent(err)
+		}
+		return err
+	})
+	c.log().Debug("request finished", "provider", p.name, "model", model,
+		"duration", time.Since(start).Round(time.Millisecond), "attempts", attempts)
+
+	if err != nil && req.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		// The provider's own error (e.g. "signal: killed") hides the cause
+		return Response{}, &Error{
+			Kind:     KindProviderFailed,
+			Msg:      fmt.Sprintf("request timed out after %s", req.Timeout),
+			Provider: p.name,
+			Err:      context.DeadlineExceeded,
+		}
+	}
+	if err != nil && isContextLength(err) {
+		return Response{}, &Error{
+			Kind:     KindProviderFailed,
+			Msg:      fmt.Sprintf("prompt is too long for %s (about %d tokens)", model, EstimateTokens(req.Prompt)),
+			Provider: p.name,
+			Err:      contextLengthError{err},
+		}
+	}
+	if err != nil {
+		return Response{}, &Error{Kind: KindProviderFailed, Msg: "AI request failed", Provider: p.name, Err: err}
+	}
+
+	if key != "" {
+		if err := c.cachePut(key, cacheEntry{Provider: p.name, Model: req.Model, Response: resp.Text, Time: time.Now()}); err != nil {
+			// A failed write only costs a future cache miss
+			c.log().Warn("response not cached", "error", err)
+		}
+	}
+	return c.finish(p, req, resp), nil
+}
+
+// requestModel returns the model to ask p for: req.Model, else p's entry
+// in req.Models, else p's default, with aliases resolved.
+func requestModel(p *provider, req Request) string {
+	if req.Model != "" {
+		return ResolveModel(p.name, req.Model, req.Aliases)
+	}
+	if m := req.Models[p.name]; m != "" {
+		return ResolveModel(p.name, m, req.Aliases)
+	}
+	return p.defaultModel
+}
+
+// ResolveModel returns the model ID that model names for the provider
+// given the aliases, whose keys are an alias for every provider, such as
+// "fast", or for one provider, such as "openai/fast"; the latter wins. A
+// model that is not an alias is returned unchanged.
+func ResolveModel(provider, model string, aliases map[string]string) string {
+	if id, ok := aliases[provider+"/"+model]; ok && id != "" {
+		return id
+	}
+	if id, ok := aliases[model]; ok && id != "" {
+		return id
+	}
+	return model
+}
+
+// schemaPrompt asks for a response conforming to schema, for providers
+// that cannot enforce it.
+func schemaPrompt(prompt string, schema json.RawMessage) string {
+	return prompt + "\n\nRespond with only a JSON value, without a code fence or any other text, that conforms to this JSON Schema:\n" + string(schema)
+}
+
+// finish fills in the parts of a response that p did not.
+func (c *Client) finish(p *provider, req Request, resp Response) Response {
+	resp.Provider = p.name
+	if resp.Usage == (Usage{}) {
+		resp.Usage = Usage{
+			InputTokens:  EstimateTokens(req.System) + EstimateTokens(req.Prompt),
+			OutputTokens: EstimateTokens(resp.Text),
+			Estimated:    true,
+		}
+	}
+	return resp
+}
+
+// race sends req to every provider at once and returns the first
+// successful response, cancelling the rest. It only returns once every
+// provider has stopped, so no CLI process outlives it. The request fails
+// only if every provider fails.
+func (c *Client) race(ctx context.Context, racers []*provider, req Request) (Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	names := make([]string, len(racers))
+	for i, p := range racers {
+		names[i] = p.name
+	}
+	c.log().Debug("racing providers", "providers", strings.Join(names, ","))
+
+	// Interleaved output from several providers would be unreadable, so
+	// only the winner's response is streamed, once it is known
+	stream := req.Stream
+	req.Stream = nil
+
+	type result struct {
+		name string
+		resp Response
+		err  error
+	}
+	results := make(chan result, len(racers))
+	var wg sync.WaitGroup
+	for _, p := range racers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.ask(ctx, p, req)
+			results <- result{p.name, resp, err}
+		}()
+	}
+	defer wg.Wait()
+
+	var errs []error
+	for range racers {
+		r := <-results
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, r.err))
+			continue
+		}
+
+		c.log().Debug("race won", "provider", r.name)
+		cancel()
+		if stream != nil {
+			io.WriteString(stream, r.resp.Text)
+		}
+		return r.resp, nil
+	}
+	return Response{}, &Error{Kind: KindProviderFailed, Msg: "every provider failed", Err: errors.Join(errs...)}
+}
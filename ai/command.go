@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// commandName matches the names command providers may be registered under.
+var commandName = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+var (
+	commandMu sync.RWMutex
+	// commandProviders are the providers registered with RegisterCommand,
+	// tried after the built-in ones.
+	commandProviders []*provider
+)
+
+// CommandProvider describes a provider that runs a command for each
+// request, such as a gateway's CLI, and answers with what it prints.
+type CommandProvider struct {
+	// Command is the command line, split into words as a shell would but
+	// with nothing expanded. Each word after the program is a text/template
+	// given the request's .Prompt and .Model, and left out if it expands to
+	// nothing, so --model={{.Model}} can be written
+	// {{if .Model}}--model={{.Model}}{{end}}. A command that does not use
+	// .Prompt gets the prompt on its standard input.
+	Command string
+	// Model is used when a request names no model for the provider.
+	Model string
+	// Local is set if the command runs the model on this machine, so that
+	// Request.LocalOnly allows it.
+	Local bool
+}
+
+// commandData is what the words of a command provider's Command are
+// executed with.
+type commandData struct {
+	Prompt string
+	Model  string
+}
+
+// command is a parsed CommandProvider.
+type command struct {
+	name string
+	// program is the command to run, found through PATH unless it is a
+	// path, and args are the templates of its arguments.
+	program string
+	args    []*template.Template
+	// stdin is set if no argument has the prompt.
+	stdin bool
+}
+
+// RegisterCommand adds a provider with the given name that runs cp's
+// command, or replaces the command provider of that name. The name must
+// not be one of the built-in providers. Command providers are tried after
+// the built-in ones by the auto provider.
+func RegisterCommand(name string, cp CommandProvider) error {
+	c, err := parseCommand(name, cp)
+	if err != nil {
+		return err
+	}
+	p := &provider{
+		name:         name,
+		available:    lookPathAvailable(c.program),
+		ask:          c.ask,
+		probe:        commandProbe(c.program),
+		defaultModel: cp.Model,
+		local:        cp.Local,
+	}
+
+	commandMu.Lock()
+	defer commandMu.Unlock()
+	for i, q := range commandProviders {
+		if q.name == name {
+			commandProviders[i] = p
+			return nil
+		}
+	}
+	commandProviders = append(commandProviders, p)
+	return nil
+}
+
+// CheckCommand returns an error unless cp could be registered under name
+// with RegisterCommand.
+func CheckCommand(name string, cp CommandProvider) error {
+	_, err := parseCommand(name, cp)
+	return err
+}
+
+func parseCommand(name string, cp CommandProvider) (*command, error) {
+	if !commandName.MatchString(name) || name == Auto {
+		return nil, fmt.Errorf("invalid provider name %q: want lowercase letters, digits, '.', '_', or '-'", name)
+	}
+	for _, p := range providers {
+		if p.name == name {
+			return nil, fmt.Errorf("provider %s is built in and cannot be replaced by a command", name)
+		}
+	}
+
+	words, err := splitCommand(cp.Command)
+	if err != nil {
+		return nil, fmt.Errorf("provider %s: command: %w", name, err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("provider %s: command is empty", name)
+	}
+	if strings.Contains(words[0], "{{") {
+		return nil, fmt.Errorf("provider %s: command: the program %q cannot be a template", name, words[0])
+	}
+
+	c := &command{name: name, program: words[0], stdin: true}
+	for _, word := range words[1:] {
+		t, err := template.New(name).Option("missingkey=error").Parse(word)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: command: %w", name, err)
+		}
+		// Catch unknown fields now rather than on the first request
+		if err := t.Execute(&strings.Builder{}, commandData{}); err != nil {
+			return nil, fmt.Errorf("provider %s: command: %w", name, err)
+		}
+		if strings.Contains(word, ".Prompt") {
+			c.stdin = false
+		}
+		c.args = append(c.args, t)
+	}
+	return c, nil
+}
+
+func (c *command) ask(ctx context.Context, req Request) (Response, error) {
+	data := commandData{Prompt: cliPrompt(req), Model: req.Model}
+	args := make([]string, 0, len(c.args))
+	for _, t := range c.args {
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return Response{}, fmt.Errorf("%s: command: %w", c.name, err)
+		}
+		if b.Len() > 0 {
+			args = append(args, b.String())
+		}
+	}
+	var stdin string
+	if c.stdin {
+		stdin = data.Prompt
+	}
+
+	text, err := runCLI(ctx, c.program, args, stdin, req.Stream)
+	return Response{Text: text, Model: req.Model}, err
+}
+
+// commandProbe reports where a command provider's program was found,
+// without running it: unlike the provider CLIs, it may not have a
+// --version flag.
+func commandProbe(program string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		path, err := exec.LookPath(program)
+		if err != nil {
+			return "", fmt.Errorf("%s not found in PATH", program)
+		}
+		return "runs " + path, nil
+	}
+}
+
+// splitCommand splits a command line into words at unquoted spaces and
+// tabs. Single quotes keep everything up to the next one; double quotes
+// keep everything but backslash escapes of " and \; a backslash outside
+// quotes keeps the next character. Template actions, {{ to }}, are kept
+// whole.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case strings.HasPrefix(s[i:], "{{"):
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated {{ in %q", s)
+			}
+			word.WriteString(s[i : i+end+2])
+			i += end + 1
+		case ch == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated ' in %q", s)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case ch == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated \" in %q", s)
+			}
+		case ch == '\\' && i+1 < len(s):
+			i++
+			word.WriteByte(s[i])
+		default:
+			word.WriteByte(ch)
+		}
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
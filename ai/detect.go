@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ollamaAvailableTTL is how long a check that the Ollama server is
+// reachable is reused; a server can be started or stopped at any time.
+const ollamaAvailableTTL = 5 * time.Second
+
+// detections counts calls to ResetAvailability; a cached result from an
+// earlier count is stale.
+var detections atomic.Int64
+
+// ResetAvailability discards the cached results of Available and provider
+// detection, so the next request checks for every provider again, for
+// example after installing a CLI or starting Ollama. Results are otherwise
+// kept for the life of the process, or a few seconds for the checks that
+// reach a server, and also rechecked when PATH or OLLAMA_HOST changes.
+func ResetAvailability() {
+	detections.Add(1)
+}
+
+// availability caches the result of an availability check, keyed by the
+// value it depends on, such as PATH. It is safe for concurrent use, and
+// concurrent callers share one check.
+type availability struct {
+	mu sync.Mutex
+	// key, generation, and at describe the cached err.
+	key        string
+	generation int64
+	at         time.Time
+	err        error
+	valid      bool
+}
+
+// cachedCheck returns check with its result cached for each value of key,
+// for ttl if it is positive or else for the life of the process, until
+// ResetAvailability.
+func cachedCheck(key func() string, ttl time.Duration, check func() error) func() error {
+	var a availability
+	return func() error {
+		k, gen := key(), detections.Load()
+
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.valid && a.key == k && a.generation == gen && (ttl <= 0 || time.Since(a.at) < ttl) {
+			return a.err
+		}
+		a.err = check()
+		a.key, a.generation, a.at, a.valid = k, gen, time.Now(), true
+		return a.err
+	}
+}
+
+// pathEnv returns PATH, which the CLI providers are found through.
+func pathEnv() string {
+	return os.Getenv("PATH")
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorKind classifies the errors returned by Client.Ask.
+type ErrorKind int
+
+const (
+	// KindNoProvider means no usable provider was found, or the requested
+	// provider is unknown or unavailable.
+	KindNoProvider ErrorKind = iota + 1
+	// KindProviderFailed means the provider was called but the request
+	// failed or timed out.
+	KindProviderFailed
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNoProvider:
+		return "no provider"
+	case KindProviderFailed:
+		return "provider failed"
+	}
+	return "unknown"
+}
+
+// Error is an error with a Kind that callers can inspect with errors.As.
+// errors.Is reports whether an *Error has the same Kind as the sentinel, so
+// errors.Is(err, ErrNoProvider) holds for any no-provider error regardless
+// of its message.
+type Error struct {
+	Kind ErrorKind
+	// Msg is the human-readable description.
+	Msg string
+	// Provider is the provider that failed, if the error concerns one.
+	Provider string
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is matches any *Error of the same Kind.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrNoProvider     = &Error{Kind: KindNoProvider, Msg: "no AI provider available"}
+	ErrProviderFailed = &Error{Kind: KindProviderFailed, Msg: "AI request failed"}
+)
+
+// ErrEmptyResponse is the cause of a failed request whose provider
+// succeeded but returned only whitespace.
+var ErrEmptyResponse = errors.New("provider returned an empty response")
+
+// ErrContextLength is the cause of a failed request whose prompt was too
+// long for the model's context window.
+var ErrContextLength = errors.New("prompt is too long for the model's context window")
+
+// contextLengthMarkers are phrases with which providers reject a prompt
+// that does not fit in the model's context window.
+var contextLengthMarkers = []string{
+	"prompt is too long", "context_length_exceeded", "maximum context length",
+	"context window", "input token count", "exceeds the maximum number of tokens",
+	"too many tokens",
+}
+
+// contextLengthError is a provider's error that errors.Is reports as
+// ErrContextLength as well.
+type contextLengthError struct{ err error }
+
+func (e contextLengthError) Error() string   { return e.err.Error() }
+func (e contextLengthError) Unwrap() []error { return []error{ErrContextLength, e.err} }
+
+// isContextLength reports whether err is a provider rejecting a prompt as
+// too long for the model.
+func isContextLength(err error) bool {
+	var msg string
+	var apiErr *apiError
+	var cliErr *cliError
+	switch {
+	case errors.As(err, &apiErr):
+		if apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusRequestEntityTooLarge {
+			return false
+		}
+		msg = apiErr.Body
+	case errors.As(err, &cliErr):
+		msg = cliErr.Stderr
+	default:
+		return false
+	}
+	msg = strings.ToLower(msg)
+	for _, marker := range contextLengthMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrNoModelList is returned by Models for a provider that cannot list the
+// models it accepts.
+var ErrNoModelList = errors.New("provider cannot list its models")
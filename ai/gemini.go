@@ -0,0 +1,288 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	geminiBaseURL      = "https://generativelanguage.googleapis.com/v1beta"
+	geminiDefaultModel = "gemini-1.5-flash"
+)
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiGenerationConfig struct {
+	MaxOutputTokens  int      `json:"maxOutputTokens,omitempty"`
+	Temperature      *float64 `json:"temperature,omitempty"`
+	TopP             *float64 `json:"topP,omitempty"`
+	ResponseMIMEType string   `json:"responseMimeType,omitempty"`
+}
+
+type geminiRequest struct {
+	Contents          []geminiContent         `json:"contents"`
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+// geminiResponse is a GenerateContentResponse, the whole response or, when
+// streaming, one chunk of it.
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// geminiErrorBody is the body of a non-200 Gemini API response.
+type geminiErrorBody struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+type geminiModelList struct {
+	Models []struct {
+		Name                       string   `json:"name"`
+		DisplayName                string   `json:"displayName"`
+		SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+	} `json:"models"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// geminiHeader returns the authentication headers for the Gemini API.
+// It requires GEMINI_API_KEY to be set.
+func geminiHeader() (http.Header, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY is not set")
+	}
+
+	header := http.Header{}
+	header.Set("x-goog-api-key", apiKey)
+	return header, nil
+}
+
+// geminiURL returns the URL of path on the Gemini API, as apiURL does, with
+// query added to any query the base URL has.
+func geminiURL(base, path string, query url.Values) (string, error) {
+	endpoint, err := apiURL(Gemini, base, path)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for k, v := range query {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// geminiModel returns the model name for the API path, accepting the
+// "models/" prefix the API lists them with.
+func geminiModel(model string) string {
+	return strings.TrimPrefix(model, "models/")
+}
+
+// geminiError replaces the JSON body of a Gemini API error with its message,
+// keeping the status code for retries.
+func geminiError(err error) error {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	var body geminiErrorBody
+	if json.Unmarshal([]byte(apiErr.Body), &body) == nil && body.Error.Message != "" {
+		apiErr.Body = body.Error.Message
+		if body.Error.Status != "" {
+			apiErr.Body = body.Error.Status + ": " + body.Error.Message
+		}
+	}
+	return err
+}
+
+// geminiModels lists the models available to the API key that can
+// generate content.
+func geminiModels(ctx context.Context) ([]Model, error) {
+	header, err := geminiHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	var models []Model
+	page := ""
+	for {
+		query := url.Values{"pageSize": {"1000"}}
+		if page != "" {
+			query.Set("pageToken", page)
+		}
+		endpoint, err := geminiURL("", "/models", query)
+		if err != nil {
+			return nil, err
+		}
+		var list geminiModelList
+		if err := getJSON(ctx, "gemini", endpoint, header, &list); err != nil {
+			return nil, geminiError(err)
+		}
+		for _, m := range list.Models {
+			for _, method := range m.SupportedGenerationMethods {
+				if method == "generateContent" {
+					models = append(models, Model{ID: geminiModel(m.Name), Provider: Gemini, Description: m.DisplayName})
+					break
+				}
+			}
+		}
+		if list.NextPageToken == "" {
+			break
+		}
+		page = list.NextPageToken
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+// askGemini sends a prompt to the Gemini API's generateContent method.
+// It requires GEMINI_API_KEY to be set.
+func askGemini(ctx context.Context, req Request) (Response, error) {
+	header, err := geminiHeader()
+	if err != nil {
+		return Response{}, err
+	}
+
+	model := geminiModel(req.Model)
+	method, query := ":generateContent", url.Values{}
+	if req.Stream != nil {
+		method, query = ":streamGenerateContent", url.Values{"alt": {"sse"}}
+	}
+	endpoint, err := geminiURL(req.BaseURL, "/models/"+model+method, query)
+	if err != nil {
+		return Response{}, err
+	}
+
+	body := geminiRequest{
+		Contents: []geminiContent{{Role: "user", Parts: []geminiPart{{Text: req.Prompt}}}},
+	}
+	if req.System != "" {
+		body.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: req.System}}}
+	}
+	config := geminiGenerationConfig{
+		MaxOutputTokens: req.MaxOutputTokens,
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+	}
+	if len(req.Schema) > 0 {
+		// The schema itself is in the prompt: Gemini takes only a subset
+		// of JSON Schema
+		config.ResponseMIMEType = "application/json"
+	}
+	if config != (geminiGenerationConfig{}) {
+		body.GenerationConfig = &config
+	}
+
+	resp, err := postJSON(ctx, "gemini", endpoint, header, body)
+	if err != nil {
+		return Response{}, geminiError(err)
+	}
+	defer resp.Body.Close()
+
+	if req.Stream != nil {
+		return streamGemini(resp.Body, req.Stream, model)
+	}
+
+	var parsed geminiResponse
+	if err := decodeJSON("gemini", resp.Body, &parsed); err != nil {
+		return Response{}, err
+	}
+	text, err := parsed.text()
+	if err != nil {
+		return Response{}, err
+	}
+	if text == "" {
+		if reason := parsed.Candidates[0].FinishReason; reason != "" && reason != "STOP" {
+			return Response{}, fmt.Errorf("gemini: response stopped with no text (%s)", reason)
+		}
+	}
+
+	return Response{
+		Text:  strings.TrimSpace(text),
+		Model: model,
+		Usage: parsed.usage(),
+	}, nil
+}
+
+// text returns the text of the first candidate, or an error if the prompt
+// was blocked and there is none.
+func (r *geminiResponse) text() (string, error) {
+	if len(r.Candidates) == 0 {
+		if r.PromptFeedback.BlockReason != "" {
+			return "", fmt.Errorf("gemini: prompt blocked (%s)", r.PromptFeedback.BlockReason)
+		}
+		return "", fmt.Errorf("gemini: response contained no candidates")
+	}
+	var text strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String(), nil
+}
+
+func (r *geminiResponse) usage() Usage {
+	return Usage{InputTokens: r.UsageMetadata.PromptTokenCount, OutputTokens: r.UsageMetadata.CandidatesTokenCount}
+}
+
+// streamGemini consumes a streamGenerateContent event stream, copying the
+// text of each chunk to w as it arrives. The last chunk carries the usage.
+func streamGemini(r io.Reader, w io.Writer, model string) (Response, error) {
+	var text strings.Builder
+	var last geminiResponse
+	err := readSSE(r, func(_, data string) error {
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("gemini: decode stream chunk: %w", err)
+		}
+		delta, err := chunk.text()
+		if err != nil && chunk.PromptFeedback.BlockReason != "" {
+			return err
+		}
+		last = chunk
+		text.WriteString(delta)
+		_, err = io.WriteString(w, delta)
+		return err
+	})
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{Text: strings.TrimSpace(text.String()), Model: model, Usage: last.usage()}, nil
+}
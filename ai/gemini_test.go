@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGeminiURL(t *testing.T) {
+	for _, tt := range []struct {
+		base, path string
+		query      map[string][]string
+		want       string
+	}{
+		{"", "/models", map[string][]string{"pageSize": {"1000"}},
+			geminiBaseURL + "/models?pageSize=1000"},
+		{"https://gateway.example.com/gemini/", "/models/gemini-1.5-flash:generateContent", nil,
+			"https://gateway.example.com/gemini/models/gemini-1.5-flash:generateContent"},
+		// The base URL's query is kept
+		{"https://gateway.example.com/v1beta?tenant=a", "/models/m:streamGenerateContent", map[string][]string{"alt": {"sse"}},
+			"https://gateway.example.com/v1beta/models/m:streamGenerateContent?alt=sse&tenant=a"},
+	} {
+		got, err := geminiURL(tt.base, tt.path, tt.query)
+		if err != nil {
+			t.Fatalf("geminiURL(%q, %q): %v", tt.base, tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("geminiURL(%q, %q) = %s, want %s", tt.base, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAskGeminiBaseURL(t *testing.T) {
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		if key := r.Header.Get("x-goog-api-key"); key != "test-key" {
+			t.Errorf("x-goog-api-key = %q", key)
+		}
+		io.WriteString(w, `{"candidates":[{"content":{"parts":[{"text":"feat: add x"}]},"finishReason":"STOP"}]}`)
+	}))
+	defer server.Close()
+	// The server's client has no proxy: http.ProxyFromEnvironment must not
+	// read the environment before TestNewHTTPClientProxyFromEnvironment
+	SetHTTPClient(server.Client())
+	defer SetHTTPClient(nil)
+
+	resp, err := askGemini(context.Background(), Request{
+		Prompt:  "hello",
+		Model:   "gemini-1.5-flash",
+		BaseURL: server.URL + "/v1beta",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text != "feat: add x" {
+		t.Errorf("text = %q", resp.Text)
+	}
+	if want := "/v1beta/models/gemini-1.5-flash:generateContent"; gotPath != want {
+		t.Errorf("path = %s, want %s", gotPath, want)
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// HTTPOptions configures how provider APIs are reached.
+type HTTPOptions struct {
+	// CAFile is a PEM file of certificate authorities to trust in addition
+	// to the system's, such as that of a proxy that intercepts TLS.
+	CAFile string
+	// InsecureSkipVerify accepts any server certificate.
+	InsecureSkipVerify bool
+}
+
+// NewHTTPClient returns a client for provider APIs configured by opts.
+// Like http.DefaultClient, it uses the proxies named by HTTP_PROXY,
+// HTTPS_PROXY, and NO_PROXY.
+func NewHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if opts.CAFile != "" || opts.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: opts.InsecureSkipVerify}
+		if opts.CAFile != "" {
+			data, err := os.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read CA file: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return nil, fmt.Errorf("CA file %s contains no PEM certificates", opts.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// httpClient is the client set by SetHTTPClient.
+var httpClient atomic.Pointer[http.Client]
+
+// SetHTTPClient sets the client used for provider APIs by every Client, and
+// by Available, Probe, and Models. nil restores http.DefaultClient.
+func SetHTTPClient(c *http.Client) {
+	httpClient.Store(c)
+}
+
+// apiClient returns the client for provider APIs.
+func apiClient() *http.Client {
+	if c := httpClient.Load(); c != nil {
+		return c
+	}
+	return http.DefaultClient
+}
+
+// defaultBaseURLs are the usual endpoints of the APIs whose base URL can
+// be changed, by provider name.
+var defaultBaseURLs = map[string]string{
+	Anthropic: anthropicBaseURL,
+	OpenAI:    openAIBaseURL,
+	Gemini:    geminiBaseURL,
+}
+
+// baseURLs are the endpoints set by SetBaseURL, by provider name.
+var baseURLs sync.Map
+
+// SetBaseURL points the named provider's API at base instead of its usual
+// endpoint, for every Client and for Models: an OpenAI-compatible gateway
+// such as LiteLLM, an Azure OpenAI deployment, or an Anthropic proxy.
+// Requests keep their format; paths such as /chat/completions are added to
+// base, and a query in base, such as Azure's api-version, is kept. "" restores
+// the usual endpoint.
+func SetBaseURL(name, base string) error {
+	if base == "" {
+		baseURLs.Delete(name)
+		return nil
+	}
+	if err := CheckBaseURL(name, base); err != nil {
+		return err
+	}
+	baseURLs.Store(name, base)
+	return nil
+}
+
+// CheckBaseURL returns an error unless base is an http or https URL and the
+// named provider's base URL can be changed.
+func CheckBaseURL(name, base string) error {
+	if defaultBaseURLs[name] == "" {
+		return fmt.Errorf("provider %s has no base URL to change (only %s, %s, and %s do)", name, Anthropic, OpenAI, Gemini)
+	}
+	u, err := url.Parse(base)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid base URL %q for %s: want an http or https URL", base, name)
+	}
+	return nil
+}
+
+// customBaseURL returns base if it is set, or else the base URL set for
+// the named provider by SetBaseURL, if any.
+func customBaseURL(name, base string) string {
+	if base != "" {
+		return base
+	}
+	if v, ok := baseURLs.Load(name); ok {
+		return v.(string)
+	}
+	return ""
+}
+
+// apiURL returns the URL of path on the named provider's API, under base if
+// it is set, or else the base URL set by SetBaseURL or the usual one.
+func apiURL(name, base, path string) (string, error) {
+	base = customBaseURL(name, base)
+	if base == "" {
+		base = defaultBaseURLs[name]
+	} else if err := CheckBaseURL(name, base); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + path
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// apiError is a non-200 response from a provider's HTTP API.
+type apiError struct {
+	Provider   string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s API error (%s): %s", e.Provider, e.Status, e.Body)
+}
+
+// postJSON sends body as JSON to url and returns the response if the server
+// replied 200 OK. Any other status is returned as an error that includes the
+// API error body. The caller must close the returned response body.
+func postJSON(ctx context.Context, name, url string, header http.Header, body any) (*http.Response, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: encode request: %w", name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: create request: %w", name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(name, req, header)
+}
+
+// getJSON fetches url and decodes the JSON response into v. Errors are
+// reported as for postJSON.
+func getJSON(ctx context.Context, name, url string, header http.Header, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: create request: %w", name, err)
+	}
+
+	resp, err := doRequest(name, req, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeJSON(name, resp.Body, v)
+}
+
+// doRequest sends req with the extra header values and returns the response
+// if the server replied 200 OK, or an *apiError otherwise.
+func doRequest(name string, req *http.Request, header http.Header) (*http.Response, error) {
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := apiClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", name, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, &apiError{
+			Provider:   name,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       strings.TrimSpace(string(errBody)),
+		}
+	}
+
+	return resp, nil
+}
+
+// decodeJSON reads a complete JSON response body into v.
+func decodeJSON(name string, r io.Reader, v any) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("%s: decode response: %w", name, err)
+	}
+	return nil
+}
+
+// readSSE parses a server-sent events stream, calling fn with the event name
+// and data of each event. It stops at the end of the stream or when fn
+// returns an error; fn may return io.EOF to stop early without an error.
+func readSSE(r io.Reader, fn func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var event string
+	var data []string
+	dispatch := func() error {
+		if len(data) == 0 {
+			event = ""
+			return nil
+		}
+		err := fn(event, strings.Join(data, "\n"))
+		event, data = "", nil
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := dispatch(); err == io.EOF {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, ":"):
+			// Comment line
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := dispatch(); err != io.EOF {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTPClientProxyFromEnvironment(t *testing.T) {
+	// http.ProxyFromEnvironment reads the environment once per process,
+	// so this is the only test that may depend on it
+	t.Setenv("HTTPS_PROXY", "http://proxy.example.com:3128")
+	t.Setenv("NO_PROXY", "internal.example.com")
+
+	client, err := NewHTTPClient(HTTPOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := client.Transport.(*http.Transport)
+
+	for _, tt := range []struct {
+		url, proxy string
+	}{
+		{"https://api.openai.com/v1/chat/completions", "http://proxy.example.com:3128"},
+		{"https://internal.example.com/v1", ""},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		proxy, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.url, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != tt.proxy {
+			t.Errorf("%s: proxy %q, want %q", tt.url, got, tt.proxy)
+		}
+	}
+}
+
+func TestNewHTTPClientTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, cert, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		opts HTTPOptions
+		ok   bool
+	}{
+		{"system CAs only", HTTPOptions{}, false},
+		{"extra CA file", HTTPOptions{CAFile: caFile}, true},
+		{"skip verify", HTTPOptions{InsecureSkipVerify: true}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewHTTPClient(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if ok := err == nil; ok != tt.ok {
+				t.Errorf("request succeeded %v, want %v (err %v)", ok, tt.ok, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClientBadCAFile(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		file, want string
+	}{
+		{notPEM, "contains no PEM certificates"},
+		{filepath.Join(dir, "missing.pem"), "read CA file"},
+	} {
+		_, err := NewHTTPClient(HTTPOptions{CAFile: tt.file})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want one containing %q", tt.file, err, tt.want)
+		}
+	}
+}
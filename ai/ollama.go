@@ -0,0 +1,189 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	ollamaDefaultHost  = "http://localhost:11434"
+	ollamaDefaultModel = "llama3"
+	ollamaProbeTimeout = 500 * time.Millisecond
+)
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	System string `json:"system,omitempty"`
+	Stream bool   `json:"stream"`
+	// Format is a JSON Schema for structured output (Ollama 0.5 and later)
+	Format  json.RawMessage `json:"format,omitempty"`
+	Options *ollamaOptions  `json:"options,omitempty"`
+}
+
+type ollamaOptions struct {
+	// NumPredict is the most tokens to generate
+	NumPredict  int      `json:"num_predict,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Done     bool   `json:"done"`
+	Error    string `json:"error"`
+	// Token counts, set on the final chunk
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+}
+
+func (r ollamaResponse) usage() Usage {
+	return Usage{InputTokens: r.PromptEvalCount, OutputTokens: r.EvalCount}
+}
+
+// ollamaHost returns the base URL of the Ollama server, honoring OLLAMA_HOST.
+func ollamaHost() string {
+	host := os.Getenv("OLLAMA_HOST")
+	if host == "" {
+		return ollamaDefaultHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+// ollamaAvailable reports whether the Ollama server is reachable.
+func ollamaAvailable() error {
+	ctx, cancel := context.WithTimeout(context.Background(), ollamaProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ollamaHost()+"/api/tags", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := apiClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("ollama not reachable at %s", ollamaHost())
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama at %s returned %s", ollamaHost(), resp.Status)
+	}
+	return nil
+}
+
+type ollamaTags struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// ollamaModels lists the models installed on the Ollama server.
+func ollamaModels(ctx context.Context) ([]Model, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ollamaHost()+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := apiClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama API error (%s)", resp.Status)
+	}
+
+	var tags ollamaTags
+	if err := decodeJSON("ollama", resp.Body, &tags); err != nil {
+		return nil, err
+	}
+
+	models := make([]Model, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		models = append(models, Model{ID: m.Name, Provider: Ollama, Description: "installed locally"})
+	}
+	return models, nil
+}
+
+// askOllama sends a prompt to a local Ollama server.
+func askOllama(ctx context.Context, req Request) (Response, error) {
+	model := req.Model
+
+	body := ollamaRequest{
+		Model:  model,
+		Prompt: req.Prompt,
+		System: req.System,
+		Stream: req.Stream != nil,
+		Format: req.Schema,
+	}
+	if req.MaxOutputTokens > 0 || req.Temperature != nil || req.TopP != nil {
+		body.Options = &ollamaOptions{
+			NumPredict:  req.MaxOutputTokens,
+			Temperature: req.Temperature,
+			TopP:        req.TopP,
+		}
+	}
+
+	resp, err := postJSON(ctx, "ollama", ollamaHost()+"/api/generate", nil, body)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	if req.Stream != nil {
+		return streamOllama(resp.Body, req.Stream, model)
+	}
+
+	var parsed ollamaResponse
+	if err := decodeJSON("ollama", resp.Body, &parsed); err != nil {
+		return Response{}, err
+	}
+	if parsed.Error != "" {
+		return Response{}, fmt.Errorf("ollama: %s", parsed.Error)
+	}
+
+	return Response{Text: strings.TrimSpace(parsed.Response), Model: model, Usage: parsed.usage()}, nil
+}
+
+// streamOllama consumes Ollama's newline-delimited JSON stream, copying
+// response fragments to w as they arrive.
+func streamOllama(r io.Reader, w io.Writer, model string) (Response, error) {
+	var text strings.Builder
+	var usage Usage
+	dec := json.NewDecoder(r)
+	for {
+		var chunk ollamaResponse
+		if err := dec.Decode(&chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			return Response{}, fmt.Errorf("ollama: decode stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return Response{}, fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		text.WriteString(chunk.Response)
+		if _, err := io.WriteString(w, chunk.Response); err != nil {
+			return Response{}, err
+		}
+		if chunk.Done {
+			usage = chunk.usage()
+			break
+		}
+	}
+
+	return Response{Text: strings.TrimSpace(text.String()), Model: model, Usage: usage}, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	openAIBaseURL      = "https://api.openai.com/v1"
+	openAIDefaultModel = "gpt-4o-mini"
+)
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Stream         bool                  `json:"stream,omitempty"`
+	MaxTokens      int                   `json:"max_tokens,omitempty"`
+	Temperature    *float64              `json:"temperature,omitempty"`
+	TopP           *float64              `json:"top_p,omitempty"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIResponseFormat asks for structured output matching a JSON Schema.
+type openAIResponseFormat struct {
+	Type       string `json:"type"`
+	JSONSchema struct {
+		Name   string          `json:"name"`
+		Schema json.RawMessage `json:"schema"`
+	} `json:"json_schema"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta openAIMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+type openAIModelList struct {
+	Data []struct {
+		ID      string `json:"id"`
+		OwnedBy string `json:"owned_by"`
+	} `json:"data"`
+}
+
+// openAIHeader returns the authentication headers for the OpenAI API at
+// endpoint. Azure OpenAI takes the key in an api-key header instead of as
+// a bearer token. It requires OPENAI_API_KEY to be set.
+func openAIHeader(endpoint string) (http.Header, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY is not set")
+	}
+
+	header := http.Header{}
+	if u, err := url.Parse(endpoint); err == nil && strings.HasSuffix(u.Hostname(), ".openai.azure.com") {
+		header.Set("api-key", apiKey)
+	} else {
+		header.Set("Authorization", "Bearer "+apiKey)
+	}
+	return header, nil
+}
+
+// openAIModels lists the models available to the API key.
+func openAIModels(ctx context.Context) ([]Model, error) {
+	endpoint, err := apiURL(OpenAI, "", "/models")
+	if err != nil {
+		return nil, err
+	}
+	header, err := openAIHeader(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var list openAIModelList
+	if err := getJSON(ctx, "openai", endpoint, header, &list); err != nil {
+		return nil, err
+	}
+
+	models := make([]Model, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, Model{ID: m.ID, Provider: OpenAI, Description: "owned by " + m.OwnedBy})
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+// askOpenAI sends a prompt to the OpenAI Chat Completions API.
+// It requires OPENAI_API_KEY to be set.
+func askOpenAI(ctx context.Context, req Request) (Response, error) {
+	endpoint, err := apiURL(OpenAI, req.BaseURL, "/chat/completions")
+	if err != nil {
+		return Response{}, err
+	}
+	header, err := openAIHeader(endpoint)
+	if err != nil {
+		return Response{}, err
+	}
+
+	model := req.Model
+
+	var messages []openAIMessage
+	if req.System != "" {
+		messages = append(messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: req.Prompt})
+
+	body := openAIRequest{
+		Model:       model,
+		Messages:    messages,
+		Stream:      req.Stream != nil,
+		MaxTokens:   req.MaxOutputTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+	if len(req.Schema) > 0 {
+		// Not strict, which rejects many ordinary schemas; callers validate
+		body.ResponseFormat = &openAIResponseFormat{Type: "json_schema"}
+		body.ResponseFormat.JSONSchema.Name = "response"
+		body.ResponseFormat.JSONSchema.Schema = req.Schema
+	}
+
+	resp, err := postJSON(ctx, "openai", endpoint, header, body)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	if req.Stream != nil {
+		// Streamed chunks carry no usage, so the Client estimates it
+		text, err := streamOpenAI(resp.Body, req.Stream)
+		return Response{Text: text, Model: model}, err
+	}
+
+	var parsed openAIResponse
+	if err := decodeJSON("openai", resp.Body, &parsed); err != nil {
+		return Response{}, err
+	}
+
+	if len(parsed.Choices) == 0 {
+		return Response{}, fmt.Errorf("openai: response contained no choices")
+	}
+
+	return Response{
+		Text:  strings.TrimSpace(parsed.Choices[0].Message.Content),
+		Model: model,
+		Usage: Usage{InputTokens: parsed.Usage.PromptTokens, OutputTokens: parsed.Usage.CompletionTokens},
+	}, nil
+}
+
+// streamOpenAI consumes a Chat Completions event stream, copying content
+// deltas to w as they arrive.
+func streamOpenAI(r io.Reader, w io.Writer) (string, error) {
+	var text strings.Builder
+	err := readSSE(r, func(_, data string) error {
+		if data == "[DONE]" {
+			return io.EOF
+		}
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("openai: decode stream chunk: %w", err)
+		}
+		for _, choice := range chunk.Choices {
+			text.WriteString(choice.Delta.Content)
+			if _, err := io.WriteString(w, choice.Delta.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(text.String()), nil
+}
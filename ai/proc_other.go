@@ -0,0 +1,12 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package ai
+
+import "os/exec"
+
+// killProcessGroup is a no-op where process groups are unavailable; the
+// process itself is still killed when its context is cancelled.
+func killProcessGroup(cmd *exec.Cmd) {}
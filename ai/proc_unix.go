@@ -0,0 +1,21 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package ai
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup makes cmd lead its own process group and, when its
+// context is cancelled, kills the whole group so that children spawned by
+// the provider CLI do not outlive it.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Provider names accepted by Request.Provider.
+const (
+	// Auto picks the first available provider in Request.Order.
+	Auto      = "auto"
+	Claude    = "claude"
+	Codex     = "codex"
+	Anthropic = "anthropic"
+	OpenAI    = "openai"
+	Gemini    = "gemini"
+	Ollama    = "ollama"
+)
+
+// provider describes an AI backend that a Client can dispatch to.
+type provider struct {
+	name string
+	// available returns nil if the provider can be used, or an error
+	// explaining why it cannot.
+	available func() error
+	// ask sends the request. The Response's Provider is filled in by the
+	// Client, and its Usage estimated if the provider leaves it zero.
+	ask func(ctx context.Context, req Request) (Response, error)
+	// models lists the models the provider accepts; nil if unknown.
+	models func(ctx context.Context) ([]Model, error)
+	// probe checks an available provider more thoroughly, returning a
+	// short description on success.
+	probe func(ctx context.Context) (string, error)
+	// defaultModel is used when a request names no model for the
+	// provider; "" leaves the choice to the provider itself.
+	defaultModel string
+	// schema is set if the provider enforces Request.Schema itself.
+	schema bool
+	// local is set if the provider runs on this machine rather than
+	// sending requests to a hosted service.
+	local bool
+}
+
+// Model describes a model a provider accepts for Request.Model.
+type Model struct {
+	ID          string `json:"id"`
+	Provider    string `json:"provider"`
+	Description string `json:"description,omitempty"`
+}
+
+// claudeModels are the model aliases the claude CLI accepts.
+var claudeModels = []Model{
+	{ID: "sonnet", Description: "latest Claude Sonnet"},
+	{ID: "opus", Description: "latest Claude Opus"},
+	{ID: "haiku", Description: "latest Claude Haiku"},
+}
+
+// providers lists the known providers in auto-detection order.
+var providers = []provider{
+	{
+		name:      Claude,
+		available: lookPathAvailable("claude"),
+		ask:       askClaude,
+		models:    staticModels(Claude, claudeModels...),
+		probe:     cliProbe("claude"),
+	},
+	{
+		name:      Codex,
+		available: lookPathAvailable("codex"),
+		ask:       askCodex,
+		probe:     cliProbe("codex"),
+	},
+	{
+		name:         Anthropic,
+		available:    envAvailable("ANTHROPIC_API_KEY"),
+		ask:          askAnthropic,
+		models:       anthropicModels,
+		probe:        envProbe("ANTHROPIC_API_KEY"),
+		defaultModel: anthropicDefaultModel,
+	},
+	{
+		name:         OpenAI,
+		available:    envAvailable("OPENAI_API_KEY"),
+		ask:          askOpenAI,
+		models:       openAIModels,
+		probe:        envProbe("OPENAI_API_KEY"),
+		defaultModel: openAIDefaultModel,
+		schema:       true,
+	},
+	{
+		name:         Gemini,
+		available:    envAvailable("GEMINI_API_KEY"),
+		ask:          askGemini,
+		models:       geminiModels,
+		probe:        envProbe("GEMINI_API_KEY"),
+		defaultModel: geminiDefaultModel,
+	},
+	{
+		name:         Ollama,
+		available:    cachedCheck(ollamaHost, ollamaAvailableTTL, ollamaAvailable),
+		ask:          askOllama,
+		models:       ollamaModels,
+		probe:        ollamaProbe,
+		defaultModel: ollamaDefaultModel,
+		schema:       true,
+		local:        true,
+	},
+}
+
+// lookPathAvailable checks that bin is on PATH, once per value of PATH.
+func lookPathAvailable(bin string) func() error {
+	return cachedCheck(pathEnv, 0, func() error {
+		if _, err := exec.LookPath(bin); err != nil {
+			return fmt.Errorf("%s CLI not found in PATH", bin)
+		}
+		return nil
+	})
+}
+
+func envAvailable(key string) func() error {
+	return func() error {
+		if os.Getenv(key) == "" {
+			return fmt.Errorf("%s is not set", key)
+		}
+		return nil
+	}
+}
+
+// staticModels returns a models function for a provider with a known list.
+func staticModels(provider string, models ...Model) func(context.Context) ([]Model, error) {
+	for i := range models {
+		models[i].Provider = provider
+	}
+	return func(context.Context) ([]Model, error) {
+		return models, nil
+	}
+}
+
+// cliProbe checks that a provider CLI runs, reporting its version.
+func cliProbe(bin string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		path, err := exec.LookPath(bin)
+		if err != nil {
+			return "", fmt.Errorf("%s CLI not found in PATH", bin)
+		}
+
+		out, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%s --version failed: %v", bin, err)
+		}
+
+		version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+		return fmt.Sprintf("%s (%s)", path, version), nil
+	}
+}
+
+// envProbe reports that an API key is set without revealing it.
+func envProbe(key string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		return key + " is set", nil
+	}
+}
+
+func ollamaProbe(ctx context.Context) (string, error) {
+	return "reachable at " + ollamaHost(), nil
+}
+
+// allProviders returns the built-in providers followed by the command
+// providers, in auto-detection order.
+func allProviders() []*provider {
+	commandMu.RLock()
+	defer commandMu.RUnlock()
+	all := make([]*provider, 0, len(providers)+len(commandProviders))
+	for i := range providers {
+		all = append(all, &providers[i])
+	}
+	return append(all, commandProviders...)
+}
+
+// Providers returns the names of the known providers, including those
+// registered with RegisterCommand, in auto-detection order, not including
+// Auto.
+func Providers() []string {
+	all := allProviders()
+	names := make([]string, len(all))
+	for i, p := range all {
+		names[i] = p.name
+	}
+	return names
+}
+
+// DefaultModel returns the model the named provider uses when a request
+// names none, or "" if the provider chooses for itself (the CLIs) or is
+// unknown.
+func DefaultModel(name string) string {
+	p, err := findProvider(name)
+	if err != nil {
+		return ""
+	}
+	return p.defaultModel
+}
+
+// Local reports whether the named provider runs on this machine, such as
+// Ollama, rather than sending requests to a hosted service. Ollama counts
+// as local wherever OLLAMA_HOST points.
+func Local(name string) bool {
+	p, err := findProvider(name)
+	return err == nil && p.local
+}
+
+// localProviders returns the names of the local providers.
+func localProviders() []string {
+	var names []string
+	for _, p := range allProviders() {
+		if p.local {
+			names = append(names, p.name)
+		}
+	}
+	return names
+}
+
+// Available returns nil if the named provider can be used, or an error
+// explaining why it cannot.
+func Available(name string) error {
+	p, err := findProvider(name)
+	if err != nil {
+		return err
+	}
+	return p.available()
+}
+
+// Probe checks a provider that Available reports usable more thoroughly,
+// for example by running its CLI, and describes it on success.
+func Probe(ctx context.Context, name string) (string, error) {
+	p, err := findProvider(name)
+	if err != nil {
+		return "", err
+	}
+	if p.probe == nil {
+		return "available", nil
+	}
+	return p.probe(ctx)
+}
+
+// Models lists the models the named provider accepts. It returns
+// ErrNoModelList if the provider cannot list them.
+func Models(ctx context.Context, name string) ([]Model, error) {
+	p, err := findProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	if p.models == nil {
+		return nil, ErrNoModelList
+	}
+	return p.models(ctx)
+}
+
+// Select returns the provider that a Request with the given Provider and
+// Order would be sent to.
+func Select(name string, order []string) (string, error) {
+	p, err := selectProvider(name, order, false)
+	if err != nil {
+		return "", err
+	}
+	return p.name, nil
+}
+
+// selectProvider returns the named provider, or with the auto provider the
+// first available one in order, which defaults to every provider in
+// auto-detection order. It fails if a provider is unknown or none is
+// available, or with localOnly if the named provider is hosted.
+func selectProvider(name string, order []string, localOnly bool) (*provider, error) {
+	if name == "" || name == Auto {
+		available, err := availableProviders(order, localOnly)
+		if err != nil {
+			return nil, err
+		}
+		return available[0], nil
+	}
+
+	p, err := findProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	if localOnly && !p.local {
+		return nil, &Error{
+			Kind: KindNoProvider,
+			Msg:  fmt.Sprintf("provider %s is hosted, and only local providers (%s) may be used", name, strings.Join(localProviders(), ", ")),
+		}
+	}
+	if err := p.available(); err != nil {
+		return nil, &Error{Kind: KindNoProvider, Msg: fmt.Sprintf("provider %s is not available", name), Err: err}
+	}
+	return p, nil
+}
+
+// availableProviders returns the available providers in order, which
+// defaults to every provider in auto-detection order, leaving out hosted
+// ones with localOnly. It fails if a provider is unknown or none is
+// available.
+func availableProviders(order []string, localOnly bool) ([]*provider, error) {
+	var available []*provider
+	if len(order) == 0 {
+		for _, p := range allProviders() {
+			if localOnly && !p.local {
+				continue
+			}
+			if p.available() == nil {
+				available = append(available, p)
+			}
+		}
+		if len(available) == 0 && localOnly {
+			return nil, &Error{
+				Kind: KindNoProvider,
+				Msg:  fmt.Sprintf("no local AI provider available (%s); hosted providers are not allowed", strings.Join(localProviders(), ", ")),
+			}
+		}
+		if len(available) == 0 {
+			return nil, &Error{
+				Kind: KindNoProvider,
+				Msg:  "no AI provider available (install claude or codex CLI, set ANTHROPIC_API_KEY, OPENAI_API_KEY, or GEMINI_API_KEY, or run ollama)",
+			}
+		}
+		return available, nil
+	}
+
+	for _, n := range order {
+		p, err := findProvider(n)
+		if err != nil {
+			return nil, err
+		}
+		if localOnly && !p.local {
+			continue
+		}
+		if p.available() == nil {
+			available = append(available, p)
+		}
+	}
+	if len(available) == 0 && localOnly {
+		return nil, &Error{
+			Kind: KindNoProvider,
+			Msg:  fmt.Sprintf("none of the providers %s is local and available; hosted providers are not allowed", strings.Join(order, ", ")),
+		}
+	}
+	if len(available) == 0 {
+		return nil, &Error{
+			Kind: KindNoProvider,
+			Msg:  fmt.Sprintf("none of the providers %s is available", strings.Join(order, ", ")),
+		}
+	}
+	return available, nil
+}
+
+// findProvider returns the provider with the given name, which must not be
+// the auto provider.
+func findProvider(name string) (*provider, error) {
+	for _, p := range allProviders() {
+		if p.name == name {
+			return p, nil
+		}
+	}
+	return nil, &Error{
+		Kind: KindNoProvider,
+		Msg:  fmt.Sprintf("unknown provider %q (valid: %s)", name, strings.Join(Providers(), ", ")),
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultRetries is a sensible Request.Retries for transient failures.
+const DefaultRetries = 2
+
+// backoff computes exponential retry delays with jitter.
+type backoff struct {
+	base time.Duration // delay before the first retry
+	max  time.Duration // upper bound on any single delay
+	// sleep waits for d or until ctx is done. It is a field so tests can
+	// avoid real delays.
+	sleep func(ctx context.Context, d time.Duration) error
+	// jitter returns a random fraction in [0, 1).
+	jitter func() float64
+}
+
+var defaultBackoff = backoff{
+	base:   500 * time.Millisecond,
+	max:    8 * time.Second,
+	sleep:  sleepContext,
+	jitter: rand.Float64,
+}
+
+// delay returns how long to wait before retry number attempt (starting at
+// 0). The exponential delay is jittered into its upper half so concurrent
+// clients spread out without retrying too eagerly.
+func (b backoff) delay(attempt int) time.Duration {
+	d := b.base << attempt
+	if d <= 0 || d > b.max {
+		d = b.max
+	}
+	half := d / 2
+	return half + time.Duration(b.jitter()*float64(half))
+}
+
+// retry calls fn until it succeeds, returns a non-retryable error, or has
+// been retried retries times. It stops early if ctx is done.
+func (b backoff) retry(ctx context.Context, retries int, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if attempt >= retries || !isRetryable(err) {
+			return err
+		}
+
+		if sleepErr := b.sleep(ctx, b.delay(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// permanentError marks an error that must not be retried.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps err so that retry returns it immediately.
+func permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// transientCLIMarkers are stderr fragments that indicate a provider CLI
+// failed for a reason worth retrying.
+var transientCLIMarkers = []string{
+	"rate limit", "429", "overloaded", "timeout", "timed out",
+	"502", "503", "504", "connection reset", "temporarily unavailable",
+}
+
+// isRetryable reports whether err is a transient failure: a timeout or
+// network error, an HTTP 429 or 5xx, an empty response, or a CLI failure
+// that reports one of those. Cancellation, auth failures, and other 4xx errors are permanent.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrEmptyResponse) {
+		return true
+	}
+
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == http.StatusRequestTimeout ||
+			apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// Transport failures such as refused or reset connections
+		return true
+	}
+
+	var cliErr *cliError
+	if errors.As(err, &cliErr) {
+		msg := strings.ToLower(cliErr.Stderr)
+		for _, marker := range transientCLIMarkers {
+			if strings.Contains(msg, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
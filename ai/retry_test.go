@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// testBackoff returns a backoff that records the delays it would sleep for
+// instead of sleeping, with the most jitter, so delays are their upper
+// bound.
+func testBackoff(slept *[]time.Duration) backoff {
+	return backoff{
+		base: 500 * time.Millisecond,
+		max:  8 * time.Second,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*slept = append(*slept, d)
+			return ctx.Err()
+		},
+		jitter: func() float64 { return 0.999999 },
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	var slept []time.Duration
+	b := testBackoff(&slept)
+	b.jitter = func() float64 { return 0 }
+
+	// Without jitter each delay is half the exponential one, up to max
+	want := []time.Duration{250, 500, 1000, 2000, 4000, 4000, 4000}
+	for attempt, ms := range want {
+		if got := b.delay(attempt); got != ms*time.Millisecond {
+			t.Errorf("delay(%d) = %s, want %s", attempt, got, ms*time.Millisecond)
+		}
+	}
+	// A shift past the width of a Duration still gives max
+	if got := b.delay(80); got != 4*time.Second {
+		t.Errorf("delay(80) = %s, want 4s", got)
+	}
+}
+
+func TestRetryTransient(t *testing.T) {
+	var slept []time.Duration
+	b := testBackoff(&slept)
+	overloaded := &apiError{Provider: OpenAI, StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+
+	calls := 0
+	err := b.retry(context.Background(), 10, func() error {
+		calls++
+		if calls < 7 {
+			return overloaded
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retry returned %v, want success on the 7th call", err)
+	}
+	if len(slept) != 6 {
+		t.Fatalf("slept %d times, want 6", len(slept))
+	}
+	for i := 1; i < len(slept); i++ {
+		if slept[i] < slept[i-1] {
+			t.Errorf("delays %v shrink", slept)
+		}
+	}
+	if slept[1] < 2*slept[0]*9/10 {
+		t.Errorf("delays %v do not grow exponentially", slept)
+	}
+	if last := slept[len(slept)-1]; last > 8*time.Second {
+		t.Errorf("delay %s is over the 8s cap", last)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var slept []time.Duration
+	b := testBackoff(&slept)
+	limited := &apiError{Provider: Anthropic, StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+
+	calls := 0
+	err := b.retry(context.Background(), 2, func() error {
+		calls++
+		return limited
+	})
+	if !errors.Is(err, limited) {
+		t.Errorf("retry returned %v, want the last error", err)
+	}
+	if calls != 3 {
+		t.Errorf("called %d times, want 3 (one try and 2 retries)", calls)
+	}
+}
+
+func TestRetryPermanent(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusBadRequest, http.StatusNotFound} {
+		var slept []time.Duration
+		b := testBackoff(&slept)
+		apiErr := &apiError{Provider: OpenAI, StatusCode: status, Status: http.StatusText(status)}
+
+		calls := 0
+		err := b.retry(context.Background(), 5, func() error {
+			calls++
+			return apiErr
+		})
+		if !errors.Is(err, apiErr) || calls != 1 || len(slept) != 0 {
+			t.Errorf("%d: %d calls, %d sleeps, error %v; want one call and its error", status, calls, len(slept), err)
+		}
+	}
+
+	var slept []time.Duration
+	b := testBackoff(&slept)
+	calls := 0
+	err := b.retry(context.Background(), 5, func() error {
+		calls++
+		return permanent(ErrEmptyResponse)
+	})
+	if err != ErrEmptyResponse || calls != 1 {
+		t.Errorf("permanent error: %d calls, error %v; want one call and the unwrapped error", calls, err)
+	}
+}
+
+func TestRetryStopsWhenCancelled(t *testing.T) {
+	var slept []time.Duration
+	b := testBackoff(&slept)
+	ctx, cancel := context.WithCancel(context.Background())
+	overloaded := &apiError{Provider: OpenAI, StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+
+	calls := 0
+	err := b.retry(ctx, 10, func() error {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return overloaded
+	})
+	if !errors.Is(err, overloaded) {
+		t.Errorf("retry returned %v, want the last error", err)
+	}
+	if calls != 2 {
+		t.Errorf("called %d times, want 2: the sleep after cancelling stops it", calls)
+	}
+}
+
+func TestSleepContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := sleepContext(ctx, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Errorf("sleepContext returned %v, want context.Canceled", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleepContext waited despite the cancelled context")
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// keywords are common programming language keywords and literals that
+// anonymizeDiff keeps so the shape of the code stays recognizable.
+var keywords = map[string]bool{}
+
+func init() {
+	for _, k := range strings.Fields(`
+		break case catch class const continue def default defer do elif else
+		enum export extends false final finally fn for func function go goto
+		if impl import in interface is lambda let loop match mod new nil none
+		None not null package private protected pub public raise return select
+		self static struct super switch this throw true True False try type
+		typeof use var void while with yield async await map chan string int
+		bool error byte rune float64 int64 uint range len make append`) {
+		keywords[k] = true
+	}
+}
+
+// anonymizer replaces paths, identifiers, and string literals with stable
+// placeholders, so the same name maps to the same placeholder throughout a
+// diff.
+type anonymizer struct {
+	paths    map[string]string
+	idents   map[string]string
+	literals map[string]string
+	// inHunk is set between a hunk header and the next file header, where
+	// lines are code rather than headers.
+	inHunk bool
+}
+
+// anonymizeDiff is a best-effort, one-way transform of a unified diff that
+// hides file paths, identifiers, and string literals while keeping the diff
+// structure, keywords, and punctuation. There is no way to map the result
+// back to the original.
+func anonymizeDiff(diff string) string {
+	a := &anonymizer{
+		paths:    map[string]string{},
+		idents:   map[string]string{},
+		literals: map[string]string{},
+	}
+
+	lines := strings.SplitAfter(diff, "\n")
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		next := ""
+		if i+1 < len(lines) {
+			next = lines[i+1]
+		}
+		out[i] = a.line(text, next) + line[len(text):]
+	}
+	return strings.Join(out, "")
+}
+
+// line anonymizes one line of the diff, given the line after it.
+func (a *anonymizer) line(text, next string) string {
+	// Without "diff --git" lines, as in plain diffs, the next file starts
+	// with a "---" line followed by a "+++" one; a removed line alone
+	// can start with "--" too
+	if a.inHunk && strings.HasPrefix(text, "--- ") && strings.HasPrefix(next, "+++ ") {
+		a.inHunk = false
+	}
+	if a.inHunk && text != "" && strings.ContainsRune("+- ", rune(text[0])) {
+		return text[:1] + a.code(text[1:])
+	}
+
+	switch {
+	case strings.HasPrefix(text, "diff --git "):
+		a.inHunk = false
+		rest := strings.TrimPrefix(text, "diff --git ")
+		if i := strings.LastIndex(rest, " b/"); i >= 0 {
+			return "diff --git " + a.prefixedPath(rest[:i]) + " " + a.prefixedPath(rest[i+1:])
+		}
+		return "diff --git"
+	case strings.HasPrefix(text, "--- "), strings.HasPrefix(text, "+++ "):
+		// A plain diff may follow the path with a tab and a timestamp
+		p, stamp, _ := strings.Cut(text[4:], "\t")
+		if stamp != "" {
+			stamp = "\t" + stamp
+		}
+		return text[:4] + a.prefixedPath(p) + stamp
+	case strings.HasPrefix(text, "index "):
+		// Blob hashes identify the content
+		mode := ""
+		if f := strings.Fields(text); len(f) > 2 {
+			mode = " " + f[2]
+		}
+		return "index 0000000..0000000" + mode
+	case strings.HasPrefix(text, "Binary files "):
+		return "Binary files differ"
+	case submoduleLine.MatchString(text):
+		// A changed submodule, from --submodule=log or =diff
+		a.inHunk = false
+		m := submoduleLine.FindStringSubmatchIndex(text)
+		rest := submoduleRange.ReplaceAllString(text[m[3]:], "0000000${1}0000000")
+		return "Submodule " + a.path(text[m[2]:m[3]]) + rest
+	case strings.HasPrefix(text, "  > "), strings.HasPrefix(text, "  < "):
+		// The subject of a commit a submodule moved by
+		return text[:4] + a.code(text[4:])
+	case strings.HasPrefix(text, "@@"):
+		a.inHunk = true
+		// Keep the ranges, anonymize the function context after them
+		if end := strings.Index(text[2:], "@@"); end >= 0 {
+			end += 4
+			return text[:end] + a.code(text[end:])
+		}
+		return text
+	}
+
+	for _, prefix := range []string{"rename from ", "rename to ", "copy from ", "copy to "} {
+		if strings.HasPrefix(text, prefix) {
+			return prefix + a.path(text[len(prefix):])
+		}
+	}
+
+	// Other extended header lines (modes, similarity) carry no names
+	return text
+}
+
+// submoduleRange matches the range of commit hashes on a "Submodule" line,
+// with its ".." or "..." separator.
+var submoduleRange = regexp.MustCompile(`[0-9a-f]+(\.\.\.?)[0-9a-f]+`)
+
+// prefixedPath anonymizes a path that may carry git's a/ or b/ prefix.
+func (a *anonymizer) prefixedPath(p string) string {
+	if p == "/dev/null" {
+		return p
+	}
+	for _, prefix := range []string{"a/", "b/"} {
+		if strings.HasPrefix(p, prefix) {
+			return prefix + a.path(p[len(prefix):])
+		}
+	}
+	return a.path(p)
+}
+
+// path replaces p with a numbered placeholder, keeping only its extension
+// so the language is still apparent.
+func (a *anonymizer) path(p string) string {
+	if r, ok := a.paths[p]; ok {
+		return r
+	}
+	r := fmt.Sprintf("file%d%s", len(a.paths)+1, path.Ext(p))
+	a.paths[p] = r
+	return r
+}
+
+// code anonymizes one line of source text.
+func (a *anonymizer) code(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := closingQuote(s, i)
+			b.WriteByte(c)
+			b.WriteString(a.placeholder(a.literals, "str", s[i:end]))
+			b.WriteByte(c)
+			i = end
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(s) && (isIdentStart(s[end]) || s[end] >= '0' && s[end] <= '9') {
+				end++
+			}
+			word := s[i:end]
+			if keywords[word] {
+				b.WriteString(word)
+			} else {
+				b.WriteString(a.placeholder(a.idents, "id", word))
+			}
+			i = end
+		case c >= 0x80:
+			// Non-ASCII text, e.g. in comments, is dropped
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func (a *anonymizer) placeholder(m map[string]string, kind, s string) string {
+	if r, ok := m[s]; ok {
+		return r
+	}
+	r := fmt.Sprintf("%s%d", kind, len(m)+1)
+	m[s] = r
+	return r
+}
+
+// closingQuote returns the index just past the literal that starts with the
+// quote at s[start], or len(s) if it is not closed on this line.
+func closingQuote(s string, start int) int {
+	q := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && q != '`':
+			i++
+		case s[i] == q:
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
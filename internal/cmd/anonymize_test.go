@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeDiff(t *testing.T) {
+	diff := `diff --git a/internal/billing/invoice.go b/internal/billing/invoice.go
+index 3b18e51..a9c4f02 100644
+--- a/internal/billing/invoice.go
++++ b/internal/billing/invoice.go
+@@ -10,3 +10,4 @@ func TotalForCustomer(c Customer) int {
+ 	if c.Plan == "enterprise" {
+-		return c.Seats * 40
++		return c.Seats * enterpriseRate
++		log.Printf("customer %s", c.Name)
+ 	}
+diff --git a/internal/billing/rates.go b/internal/billing/rates.go
+new file mode 100644
+--- /dev/null
++++ b/internal/billing/rates.go
+@@ -0,0 +1 @@
++const enterpriseRate = 42 // "enterprise" pricing
+`
+	got := anonymizeDiff(diff)
+
+	for _, secret := range []string{"billing", "invoice", "rates", "TotalForCustomer", "Customer", "Seats", "enterpriseRate", "enterprise", "customer %s", "3b18e51"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("%q survived:\n%s", secret, got)
+		}
+	}
+
+	want := `diff --git a/file1.go b/file1.go
+index 0000000..0000000 100644
+--- a/file1.go
++++ b/file1.go
+@@ -10,3 +10,4 @@ func id1(id2 id3) int {
+ 	if id2.id4 == "str1" {
+-		return id2.id5 * 40
++		return id2.id5 * id6
++		id7.id8("str2", id2.id9)
+ 	}
+diff --git a/file2.go b/file2.go
+new file mode 100644
+--- /dev/null
++++ b/file2.go
+@@ -0,0 +1 @@
++const id6 = 42 // "str1" id10
+`
+	// Placeholders are stable across files: enterpriseRate is id6 and
+	// "enterprise" str1 in both; keywords, numbers, and structure stay
+	if got != want {
+		t.Errorf("anonymizeDiff =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAnonymizePlainDiff(t *testing.T) {
+	// No "diff --git" lines: the second file's headers follow a hunk, and
+	// a removed SQL comment starts with "--" too
+	diff := "--- old/schema.sql\t2025-01-02 10:00:00\n" +
+		"+++ new/schema.sql\t2025-01-03 10:00:00\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		"--- accounts table\n" +
+		"+-- customers table\n" +
+		" CREATE TABLE accounts (id int);\n" +
+		"--- old/notes.txt\n" +
+		"+++ new/notes.txt\n" +
+		"@@ -1 +1 @@\n" +
+		"-secret plan\n" +
+		"+public plan\n"
+	got := anonymizeDiff(diff)
+
+	for _, secret := range []string{"schema", "notes", "accounts", "customers", "secret"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("%q survived:\n%s", secret, got)
+		}
+	}
+	lines := strings.Split(got, "\n")
+	for i, want := range map[int]string{
+		0: "--- file1.sql\t2025-01-02 10:00:00",
+		1: "+++ file2.sql\t2025-01-03 10:00:00",
+		3: "--- id1 id2",
+		6: "--- file3.txt",
+		7: "+++ file4.txt",
+	} {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i+1, lines[i], want)
+		}
+	}
+}
+
+func TestAnonymizeSubmodules(t *testing.T) {
+	// --submodule=log lists the subjects of the commits a submodule moved
+	// by; --submodule=diff follows the line with the diff inside it
+	diff := "diff --git a/main.go b/main.go\n" +
+		"--- a/main.go\n" +
+		"+++ b/main.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-package main\n" +
+		"+package app\n" +
+		"Submodule vendor/secret-lib 1a2b3c4..5d6e7f8:\n" +
+		"  > Add Acme Corp billing integration\n" +
+		"  < Drop the Initech export\n" +
+		"Submodule vendor/secret-lib contains modified content\n" +
+		"Submodule vendor/other-client contains untracked content\n" +
+		"Submodule vendor/new-client 0000000...9a8b7c6 (new submodule)\n" +
+		"Submodule vendor/secret-lib 1a2b3c4..5d6e7f8:\n" +
+		"diff --git a/vendor/secret-lib/acme.go b/vendor/secret-lib/acme.go\n" +
+		"--- a/vendor/secret-lib/acme.go\n" +
+		"+++ b/vendor/secret-lib/acme.go\n" +
+		"@@ -1 +1 @@\n" +
+		"-const plan = 1\n" +
+		"+const plan = 2\n"
+	got := anonymizeDiff(diff)
+
+	for _, secret := range []string{"secret-lib", "other-client", "new-client", "Acme", "Initech", "billing", "1a2b3c4", "5d6e7f8", "9a8b7c6"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("%q survived:\n%s", secret, got)
+		}
+	}
+	lines := strings.Split(got, "\n")
+	for i, want := range map[int]string{
+		6:  "Submodule file2 0000000..0000000:",
+		7:  "  > id3 id4 id5 id6 id7",
+		8:  "  < id8 id9 id10 export",
+		9:  "Submodule file2 contains modified content",
+		10: "Submodule file3 contains untracked content",
+		11: "Submodule file4 0000000...0000000 (new submodule)",
+		12: "Submodule file2 0000000..0000000:",
+		13: "diff --git a/file5.go b/file5.go",
+		17: "-const id11 = 1",
+	} {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i+1, lines[i], want)
+		}
+	}
+}
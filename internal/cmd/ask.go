@@ -0,0 +1,510 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/ai"
+	"github.com/yourorg/arc-ai/internal/jsonschema"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// defaultCacheTTL is how long a cached answer is reused by default.
+const defaultCacheTTL = 24 * time.Hour
+
+// askTemperature is the default sampling temperature for ask, which leaves
+// room for varied answers.
+const askTemperature = 0.7
+
+func newAskCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var stream bool
+	var continueSession, newSession bool
+	var lang string
+	var file string
+	var system string
+	var contextPaths []string
+	var maxTokens int
+	var noCache bool
+	var cacheTTL time.Duration
+	var schemaFile string
+	var width int
+	var render bool
+	var copyAnswer bool
+	var showStats bool
+	var dryRun bool
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "ask <question>",
+		Short: "Ask AI a question",
+		Long: `Ask an AI model a question and get a response.
+
+The question can be provided as arguments, read from a file with --file
+(which takes precedence over arguments), or piped via stdin.
+
+Every exchange is saved so that --continue can follow up on it;
+without --continue a new conversation is started.
+
+--lang asks for the answer in another language, such as fr or German.
+
+Answers are cached, so asking the same question of the same provider and
+model again within --cache-ttl returns the saved answer. Use --no-cache to
+always ask the provider.
+
+--context attaches files to the question; a directory attaches the files
+beneath it that are not ignored by git or by a .arc-ai-ignore file, which
+takes the same patterns as .gitignore. Attached files are truncated to
+fit within --max-tokens.
+
+--system sets a system prompt, such as "You are a terse senior Go
+reviewer.", defaulting to system in the config file.
+
+--output json or --output yaml prints the question and response as a map,
+with the provider that answered and, when known, the model.
+
+Give --model more than once to compare models: the question goes to each
+at once, and their answers are printed in turn, each under a "==> model
+(provider) <==" header, or with --output json or yaml as a map of model
+to response, provider, and model ID, or error. One model failing does not
+stop the others, but makes the command fail once all have answered.
+Comparisons are not saved for --continue, and cannot be streamed.
+
+--stats prints the input and output tokens and the time spent waiting for
+the AI to stderr after the answer, or adds them under stats with --output
+json or yaml. Counts marked ~ are estimates, for providers that do not
+report usage.
+
+--dry-run prints the provider and model that would be asked, the system
+prompt, and the prompt exactly as it would be sent, with the conversation,
+--context files, and language instruction, and exits without asking.
+--output json or yaml prints them as a map, and --output raw only the
+prompt. Given several --model flags, it shows each model's request.
+
+--output raw prints exactly the response, without a trailing newline, so
+it can be piped to tools such as pbcopy.
+
+On a terminal, the answer is word-wrapped to its width, leaving code blocks
+as they are; --width sets another width, also when stdout is not a
+terminal, and --width 0 turns wrapping off. Streamed answers are not
+wrapped.
+
+Also on a terminal, the answer's markdown is rendered with colors and
+styles: headings, bold and italic text, lists, and code blocks with
+syntax highlighting. --render=false, or NO_COLOR in the environment,
+prints it as plain text; --render styles it even when stdout is not a
+terminal, e.g. for less -R.
+
+--json-schema asks for a JSON response conforming to a JSON Schema file,
+which OpenAI and Ollama enforce and other providers are asked to follow.
+The response is validated, asked for once more if it does not conform,
+and printed as JSON on its own.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := resolveOutput(cmd, &out, outputYAML, outputRaw)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			// Several --model flags compare the models' answers
+			compare := len(aiOpts.modelFlags) > 1
+			if compare {
+				if err := checkModels(aiOpts.modelFlags); err != nil {
+					return err
+				}
+				for _, name := range []string{"stream", "continue", "json-schema", "copy"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s cannot be used with more than one --model", name)
+					}
+				}
+				if format == outputRaw {
+					return fmt.Errorf("--output raw cannot be used with more than one --model")
+				}
+			}
+			if showStats {
+				aiOpts.stats = &requestStats{}
+				defer aiOpts.stats.print(os.Stderr)
+			}
+			if !cmd.Flags().Changed("system") {
+				system = cfg.System
+			}
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var question string
+			if file != "" {
+				question, err = readFileOrStdin(file)
+				if err != nil {
+					return err
+				}
+				if strings.TrimSpace(question) == "" {
+					if file == "-" {
+						return fmt.Errorf("no question provided on stdin")
+					}
+					return fmt.Errorf("question file %s is empty", file)
+				}
+			} else if len(args) > 0 {
+				question = strings.Join(args, " ")
+			} else {
+				// Read from stdin
+				scanner := bufio.NewScanner(os.Stdin)
+				var lines []string
+				for scanner.Scan() {
+					lines = append(lines, scanner.Text())
+				}
+				question = strings.Join(lines, "\n")
+			}
+
+			if strings.TrimSpace(question) == "" {
+				return fmt.Errorf("no question provided")
+			}
+
+			var schemaText json.RawMessage
+			var schema *jsonschema.Schema
+			if schemaFile != "" {
+				schemaText, schema, err = askSchema(schemaFile)
+				if err != nil {
+					return err
+				}
+			}
+
+			// A dry run leaves the conversation as it is
+			if newSession && !dryRun {
+				if err := resetSession(); err != nil {
+					return err
+				}
+			}
+
+			sess := &session{}
+			if continueSession {
+				loaded, err := loadSession()
+				if err != nil {
+					return err
+				}
+				sess = loaded
+			}
+
+			prompt := question
+			if len(contextPaths) > 0 {
+				attached, err := askContext(ctx, contextPaths, question, maxTokens)
+				if err != nil {
+					return err
+				}
+				prompt = attached + "Question:\n" + question
+			}
+			if rule := languageRule("your answer", lang); rule != "" {
+				prompt += "\n\n" + rule
+			}
+
+			req := aiOpts.request(sess.prompt(prompt))
+			req.System = system
+			req.Schema = schemaText
+			if !noCache {
+				req.CacheTTL = cacheTTL
+			}
+			// Structured output needs the complete response, so never stream it
+			structured := format == outputYAML || out.Is(output.OutputJSON)
+			streaming := stream && !structured
+			if streaming {
+				req.Stream = os.Stdout
+			}
+
+			if dryRun {
+				return printPreviews(format, out, req, aiOpts.modelFlags)
+			}
+
+			// A question read from stdin leaves nothing to confirm with, so
+			// large requests piped in need --yes
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), req.Prompt); !ok {
+				return err
+			}
+
+			// present wraps and renders an answer for the terminal
+			present := func(response string) string {
+				switch {
+				case cmd.Flags().Changed("width"):
+				case stdoutIsTerminal():
+					width = terminalWidth(os.Stdout)
+				default:
+					width = 0
+				}
+				response = wrapText(response, width)
+
+				if !cmd.Flags().Changed("render") {
+					render = stdoutIsTerminal() && os.Getenv("NO_COLOR") == ""
+				}
+				if render {
+					response = renderMarkdown(response)
+				}
+				return response
+			}
+
+			if compare {
+				resps, errs := askModels(ctx, req, aiOpts.modelFlags)
+				if structured {
+					result := map[string]any{
+						"question":  question,
+						"responses": comparedAnswers(aiOpts.modelFlags, resps, errs),
+					}
+					if stats := aiOpts.stats.report(); stats != nil {
+						result["stats"] = stats
+					}
+					var err error
+					if format == outputYAML {
+						err = writeYAML(result)
+					} else {
+						err = output.JSON(result)
+					}
+					if err != nil {
+						return err
+					}
+					return modelsFailed(errs)
+				}
+
+				for i, model := range aiOpts.modelFlags {
+					if i > 0 {
+						fmt.Println()
+					}
+					if errs[i] != nil {
+						fmt.Printf("==> %s <==\nerror: %v\n", model, errs[i])
+						continue
+					}
+					fmt.Printf("==> %s (%s) <==\n%s\n", model, resps[i].Provider, present(resps[i].Text))
+				}
+				return modelsFailed(errs)
+			}
+
+			answer, err := askAIResponse(ctx, req)
+			if err != nil {
+				return err
+			}
+			if schema != nil {
+				answer, err = schemaAnswer(ctx, req, answer, schema)
+				if err != nil {
+					return err
+				}
+			}
+			response := answer.Text
+
+			sess.add(question, response)
+			if err := sess.save(); err != nil {
+				return err
+			}
+			if copyAnswer {
+				copyResponse(ctx, aiOpts.log, response)
+			}
+
+			result := map[string]any{
+				"question": question,
+				"response": response,
+				"provider": answer.Provider,
+			}
+			// A CLI provider's default model is not known
+			if answer.Model != "" {
+				result["model"] = answer.Model
+			}
+			if format == outputYAML || out.Is(output.OutputJSON) {
+				if stats := aiOpts.stats.report(); stats != nil {
+					result["stats"] = stats
+				}
+			}
+			if format == outputYAML {
+				return writeYAML(result)
+			}
+			if out.Is(output.OutputJSON) {
+				return output.JSON(result)
+			}
+
+			if format == outputRaw {
+				if !streaming {
+					fmt.Print(response)
+				}
+				return nil
+			}
+
+			if streaming {
+				fmt.Println()
+				return nil
+			}
+
+			if schema == nil {
+				response = present(response)
+			}
+			fmt.Println(response)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	aiOpts.setDefaultTemperature(cmd, askTemperature)
+	cmd.Flags().Lookup("model").Usage = "AI model to use (repeat to compare the answers of several)"
+	cmd.Flags().BoolVar(&stream, "stream", false, "Print the response as it is generated")
+	cmd.Flags().BoolVar(&continueSession, "continue", false, "Continue the previous conversation")
+	cmd.Flags().BoolVar(&newSession, "new", false, "Discard the previous conversation before asking")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Read the question from a file (- for stdin)")
+	cmd.Flags().StringArrayVar(&contextPaths, "context", nil, "File or directory to include in the prompt (repeatable)")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the question and --context files")
+	cmd.Flags().StringVar(&system, "system", "", "System prompt that steers the assistant's behavior")
+	cmd.Flags().StringVar(&lang, "lang", defaultLang, "Language for the answer, as a name or BCP-47 tag")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Ask the provider even if a cached answer exists")
+	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", defaultCacheTTL, "Reuse cached answers younger than this")
+	cmd.Flags().BoolVar(&render, "render", false, "Render the answer's markdown with terminal colors and styles (default: when stdout is a terminal)")
+	cmd.Flags().IntVar(&width, "width", 0, "Wrap the answer at this many columns (default: the terminal width; 0 disables wrapping)")
+	cmd.Flags().StringVar(&schemaFile, "json-schema", "", "Answer with JSON conforming to the JSON Schema in this file")
+	cmd.Flags().BoolVar(&copyAnswer, "copy", false, "Also copy the answer to the system clipboard")
+	cmd.Flags().BoolVar(&showStats, "stats", false, "Report token usage and latency on stderr, or under stats with --output json or yaml")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the provider, model, and prompt that would be sent, without asking")
+	cmd.MarkFlagsMutuallyExclusive("continue", "new")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "copy")
+	cmd.MarkFlagsMutuallyExclusive("json-schema", "stream")
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd, outputYAML, outputRaw)
+	cmd.MarkFlagsMutuallyExclusive("json-schema", "output")
+
+	return cmd
+}
+
+// printPreviews prints what ask would send for --dry-run: the request to
+// each of models when more than one is compared, otherwise req.
+func printPreviews(format string, out output.OutputOptions, req aiRequest, models []string) error {
+	if len(models) < 2 {
+		models = []string{req.Model}
+	}
+	previews := make([]promptPreview, len(models))
+	for i, model := range models {
+		r := req
+		r.Model = model
+		previews[i] = previewRequest(r)
+	}
+
+	var v any = previews
+	if len(previews) == 1 {
+		v = previews[0]
+	}
+	switch {
+	case format == outputYAML:
+		return writeYAML(v)
+	case out.Is(output.OutputJSON):
+		return output.JSON(v)
+	case format == outputRaw:
+		// Only one model: raw output cannot compare them
+		fmt.Print(previews[0].Prompt)
+		return nil
+	}
+	for i, p := range previews {
+		if i > 0 {
+			fmt.Println()
+		}
+		p.print(os.Stdout)
+	}
+	return nil
+}
+
+// askContext attaches the files named by --context, truncated so that they
+// and the question fit within maxTokens.
+func askContext(ctx context.Context, paths []string, question string, maxTokens int) (string, error) {
+	files, err := expandContextPaths(ctx, paths)
+	if err != nil {
+		return "", err
+	}
+
+	budget := 0
+	if maxTokens > 0 {
+		budget = maxTokens - estimateTokens(question)
+		if budget <= 0 {
+			return "", fmt.Errorf("the question alone exceeds the %d token budget; raise --max-tokens", maxTokens)
+		}
+	}
+
+	attached, err := attachContext(files, budget)
+	if err != nil {
+		return "", err
+	}
+	if budget > 0 && estimateTokens(attached) > budget {
+		return "", fmt.Errorf("%s of context do not fit in the %d token budget even after truncation; attach fewer files or raise --max-tokens",
+			plural(len(files), "file"), maxTokens)
+	}
+	return attached, nil
+}
+
+// askSchema reads the --json-schema file, returning it compacted for the
+// request and parsed for validation.
+func askSchema(path string) (json.RawMessage, *jsonschema.Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read JSON schema: %w", err)
+	}
+	schema, err := jsonschema.Parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return compact.Bytes(), schema, nil
+}
+
+// schemaAnswer returns resp with its JSON, indented, as the text if it
+// conforms to schema. Otherwise it asks once more, saying what was wrong.
+func schemaAnswer(ctx context.Context, req aiRequest, resp ai.Response, schema *jsonschema.Schema) (ai.Response, error) {
+	doc, err := schemaJSON(resp.Text, schema)
+	if err == nil {
+		resp.Text = doc
+		return resp, nil
+	}
+	req.Log.Debug("response does not match the schema; asking again", "error", err)
+
+	req.Prompt += "\n\nYour previous response was:\n" + resp.Text +
+		"\n\nIt was rejected because " + err.Error() +
+		". Respond again with only JSON that conforms to the schema."
+	req.CacheTTL = 0
+	resp, err = askAIResponse(ctx, req)
+	if err != nil {
+		return ai.Response{}, err
+	}
+	resp.Text, err = schemaJSON(resp.Text, schema)
+	return resp, err
+}
+
+// schemaJSON extracts the JSON in response and validates it against schema.
+func schemaJSON(response string, schema *jsonschema.Schema) (string, error) {
+	text, err := responseJSON(response)
+	if err != nil {
+		return "", err
+	}
+	var v any
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return "", fmt.Errorf("parse AI response: %w", err)
+	}
+	if err := schema.Validate(v); err != nil {
+		return "", fmt.Errorf("the AI response does not match the JSON schema: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(text), "", "  "); err != nil {
+		return "", fmt.Errorf("parse AI response: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// auditLog appends a record of each AI request to a JSONL file for
+// --audit-log.
+type auditLog struct {
+	path    string
+	command string
+	// full records prompts in full instead of by hash.
+	full bool
+}
+
+// auditRecord is one line of the audit log.
+type auditRecord struct {
+	Time           time.Time `json:"time"`
+	Command        string    `json:"command,omitempty"`
+	Provider       string    `json:"provider"`
+	Model          string    `json:"model,omitempty"`
+	PromptSHA256   string    `json:"prompt_sha256"`
+	Prompt         string    `json:"prompt,omitempty"`
+	System         string    `json:"system,omitempty"`
+	ResponseLength int       `json:"response_length"`
+	Cached         bool      `json:"cached,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// newAuditLog returns an audit log appended to path, or nil if path is
+// empty.
+func newAuditLog(path, command string, full bool) *auditLog {
+	if path == "" {
+		return nil
+	}
+	return &auditLog{path: path, command: command, full: full}
+}
+
+// middleware is an ai.Middleware that records each request, whether or not
+// it succeeded. A request that cannot be recorded fails, since the record
+// is the point of the log; the file is opened first, so that one that
+// cannot be written fails before the prompt is sent.
+func (a *auditLog) middleware() ai.Middleware {
+	return func(next ai.Handler) ai.Handler {
+		return func(ctx context.Context, req ai.Request) (ai.Response, error) {
+			f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+			if err != nil {
+				return ai.Response{}, fmt.Errorf("audit log: %w", err)
+			}
+			resp, err := next(ctx, req)
+
+			sum := sha256.Sum256([]byte(req.Prompt))
+			rec := auditRecord{
+				Time:           time.Now().UTC(),
+				Command:        a.command,
+				Provider:       requestProvider(resp, err),
+				Model:          resp.Model,
+				PromptSHA256:   hex.EncodeToString(sum[:]),
+				ResponseLength: len(resp.Text),
+				Cached:         resp.Cached,
+			}
+			if rec.Model == "" {
+				rec.Model = req.Model
+			}
+			if a.full {
+				rec.Prompt = req.Prompt
+				rec.System = req.System
+			}
+			if err != nil {
+				rec.Error = err.Error()
+			}
+
+			if werr := appendRecord(f, rec); werr != nil && err == nil {
+				err = werr
+			}
+			return resp, err
+		}
+	}
+}
+
+// appendRecord writes rec as one line to f, which is open for appending,
+// and closes it. The line is written with a single write, so records from
+// concurrent runs do not interleave.
+func appendRecord(f *os.File, rec auditRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("audit log: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("audit log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("audit log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+func TestAuditLogUnwritable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "audit.jsonl")
+	sent := false
+	handler := newAuditLog(path, "commit", false).middleware()(func(context.Context, ai.Request) (ai.Response, error) {
+		sent = true
+		return ai.Response{Text: "feat: add x"}, nil
+	})
+
+	if _, err := handler(context.Background(), ai.Request{Prompt: "diff"}); err == nil {
+		t.Error("a request with an unwritable audit log succeeded")
+	}
+	if sent {
+		t.Error("the request was sent before the audit log was opened")
+	}
+}
+
+func TestAuditLogRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	handler := newAuditLog(path, "commit", true).middleware()(func(_ context.Context, req ai.Request) (ai.Response, error) {
+		return ai.Response{Text: "feat: add x", Model: req.Model}, nil
+	})
+
+	for range 2 {
+		if _, err := handler(context.Background(), ai.Request{Prompt: "diff", Model: "m"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	n := 0
+	for dec.More() {
+		var rec auditRecord
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Command != "commit" || rec.Model != "m" || rec.Prompt != "diff" || rec.ResponseLength != len("feat: add x") {
+			t.Errorf("record %d = %+v", n, rec)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("%d records, want 2", n)
+	}
+}
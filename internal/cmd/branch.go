@@ -0,0 +1,146 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newBranchCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var prefix string
+	var dryRun bool
+	var maxTokens int
+
+	cmd := &cobra.Command{
+		Use:   "branch [description]",
+		Short: "Suggest a branch name",
+		Long: `Suggest a kebab-case branch name from a description or the staged changes.
+
+Without a description the staged diff is used. The suggestion can be
+created and checked out with 'git checkout -b'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			// A description with --dry-run is the only use that needs no repository
+			if len(args) == 0 || !dryRun {
+				if err := requireRepo(ctx); err != nil {
+					return err
+				}
+			}
+
+			var subject string
+			if len(args) > 0 {
+				subject = "Description:\n" + strings.Join(args, " ")
+			} else {
+				diff, err := gitDiff(ctx, true, diffFormat{}, nil, nil)
+				if err != nil {
+					return err
+				}
+				if len(diff) == 0 {
+					return &Error{Kind: KindNoChanges, Msg: "no staged changes (pass a description instead)"}
+				}
+				subject = "Diff:\n" + truncateDiff(diff, maxTokens)
+			}
+
+			prefixRule := `Start it with a type prefix such as "feat/", "fix/", "docs/", or "refactor/".`
+			if prefix != "" {
+				prefixRule = "Do not include any prefix."
+			}
+
+			prompt := fmt.Sprintf(`Suggest a git branch name for the following work.
+Use lowercase kebab-case words, at most 5 words, no issue numbers unless given.
+%s
+Respond with ONLY the branch name, no explanations.
+
+%s`, prefixRule, subject)
+
+			reader := bufio.NewReader(os.Stdin)
+			if ok, err := aiOpts.preflight(reader, prompt); !ok {
+				return err
+			}
+
+			response, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			name := prefix + cleanBranchName(response)
+			if err := checkBranchName(ctx, name); err != nil {
+				return err
+			}
+
+			fmt.Printf("Suggested branch name:\n%s\n", name)
+			if dryRun {
+				return nil
+			}
+
+			if !aiOpts.yes {
+				if !stdinIsTerminal() {
+					return fmt.Errorf("no terminal to confirm creating the branch; pass --yes to create it, or --dry-run")
+				}
+				fmt.Print("\nCreate and check out this branch? [Y/n]: ")
+				answer, _ := reader.ReadString('\n')
+				answer = strings.TrimSpace(strings.ToLower(answer))
+				if answer != "" && answer != "y" && answer != "yes" {
+					return &Error{Kind: KindCancelled, Msg: "branch not created"}
+				}
+			}
+
+			checkout := exec.CommandContext(ctx, "git", "checkout", "-b", name)
+			checkout.Stdout = os.Stdout
+			checkout.Stderr = os.Stderr
+			if err := checkout.Run(); err != nil {
+				return fmt.Errorf("git checkout failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Branch name prefix, e.g. feat/ (default: chosen by the AI)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the suggestion without creating the branch")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the diff sent to the AI")
+
+	return cmd
+}
+
+// cleanBranchName normalizes an AI-suggested branch name: the first line,
+// without quotes or backticks, lowercased, with spaces turned into dashes.
+func cleanBranchName(response string) string {
+	name, _, _ := strings.Cut(strings.TrimSpace(response), "\n")
+	name = strings.Trim(strings.TrimSpace(name), "`'\"")
+	name = strings.ToLower(name)
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// checkBranchName validates name against git's ref-name rules.
+func checkBranchName(ctx context.Context, name string) error {
+	if name == "" {
+		return fmt.Errorf("AI returned an empty branch name")
+	}
+	if err := exec.CommandContext(ctx, "git", "check-ref-format", "--branch", name).Run(); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// changelogSection is a group of changelog entries such as "Features".
+type changelogSection struct {
+	Title   string   `json:"title"`
+	Entries []string `json:"entries"`
+}
+
+func newChangelogCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var maxTokens int
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "changelog [<from>..<to>]",
+		Short: "Generate a changelog from commits",
+		Long: `Summarize the commits in a range as a grouped markdown changelog.
+
+The range defaults to the latest tag through HEAD, or all of HEAD's
+history if there are no tags.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if err := requireRepo(ctx); err != nil {
+				return err
+			}
+
+			rng := ""
+			if len(args) > 0 {
+				rng = args[0]
+			} else {
+				rng, err = defaultChangelogRange(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			commits, err := commitLog(ctx, rng)
+			if err != nil {
+				return err
+			}
+
+			if len(commits) == 0 {
+				if out.Is(output.OutputJSON) {
+					return output.JSON(map[string]any{
+						"range":    rng,
+						"sections": []changelogSection{},
+					})
+				}
+				fmt.Printf("No commits in %s.\n", rng)
+				return nil
+			}
+
+			log := truncateCommits(commits, maxTokens)
+
+			if out.Is(output.OutputJSON) {
+				prompt := fmt.Sprintf(`Write a changelog for the following git commits.
+Group the changes into sections such as "Features", "Fixes", "Documentation",
+and "Other", omitting empty sections. Write each entry as a short
+user-facing sentence and merge commits that describe the same change.
+Respond with ONLY a JSON array of objects with "title" (the section name)
+and "entries" (an array of strings), no explanations.
+
+Commits:
+%s`, log)
+
+				if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+					return err
+				}
+
+				response, err := askAI(ctx, aiOpts.request(prompt))
+				if err != nil {
+					return err
+				}
+
+				sections := []changelogSection{}
+				if err := decodeResponseJSON(response, &sections); err != nil {
+					return err
+				}
+
+				return output.JSON(map[string]any{
+					"range":    rng,
+					"sections": sections,
+				})
+			}
+
+			prompt := fmt.Sprintf(`Write a changelog in markdown for the following git commits.
+Group the changes under "###" headings such as "Features", "Fixes",
+"Documentation", and "Other", omitting empty sections. Write each entry as
+a short user-facing bullet and merge commits that describe the same change.
+Respond with ONLY the changelog, no explanations.
+
+Commits:
+%s`, log)
+
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+				return err
+			}
+
+			changelog, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(changelog)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the commit log sent to the AI")
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+// defaultChangelogRange returns latest-tag..HEAD, or HEAD if there are no tags.
+func defaultChangelogRange(ctx context.Context) (string, error) {
+	tag, err := git(ctx, "describe", "--tags", "--abbrev=0")
+	if err != nil || tag == "" {
+		return "HEAD", nil
+	}
+	return tag + "..HEAD", nil
+}
+
+// truncateCommits joins commit messages for a prompt, dropping the oldest
+// commits that do not fit within maxTokens.
+func truncateCommits(commits []string, maxTokens int) string {
+	var b strings.Builder
+	used := 0
+	for i, c := range commits {
+		entry := "- " + strings.ReplaceAll(c, "\n", "\n  ") + "\n"
+		if maxTokens > 0 && used+estimateTokens(entry) > maxTokens {
+			fmt.Fprintf(&b, "... (truncated: %s omitted)\n", plural(len(commits)-i, "commit"))
+			break
+		}
+		b.WriteString(entry)
+		used += estimateTokens(entry)
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommand returns the command line of the first tool found that
+// sets the system clipboard from its standard input.
+func clipboardCommand() ([]string, error) {
+	var tools [][]string
+	var hint string
+	switch runtime.GOOS {
+	case "darwin":
+		tools = [][]string{{"pbcopy"}}
+		hint = "pbcopy not found in PATH"
+	case "windows":
+		tools = [][]string{{"clip"}}
+		hint = "clip not found in PATH"
+	default:
+		tools = [][]string{
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+			// Under WSL, the Windows clipboard
+			{"clip.exe"},
+		}
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			tools = append([][]string{{"wl-copy"}}, tools...)
+		} else {
+			tools = append(tools, []string{"wl-copy"})
+		}
+		hint = "install wl-clipboard (Wayland), xclip, or xsel"
+	}
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool[0]); err == nil {
+			return tool, nil
+		}
+	}
+	return nil, fmt.Errorf("no clipboard tool found; %s", hint)
+}
+
+// copyToClipboard puts text on the system clipboard.
+func copyToClipboard(ctx context.Context, text string) error {
+	tool, err := clipboardCommand()
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, tool[0], tool[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	// Not a pipe: xclip stays in the background to serve the selection,
+	// and waiting for it to close a pipe would never end
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", tool[0], err)
+	}
+	return nil
+}
+
+// copyResponse copies text to the clipboard for --copy, warning rather
+// than failing if it cannot, since the text is printed as well.
+func copyResponse(ctx context.Context, log *slog.Logger, text string) {
+	if err := copyToClipboard(ctx, text); err != nil {
+		log.Warn("not copied to the clipboard", "error", err)
+	}
+}
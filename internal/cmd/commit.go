@@ -0,0 +1,1051 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// candidateDelimiter separates messages when several candidates are requested.
+const candidateDelimiter = "---8<---"
+
+// commitTemperature is the default sampling temperature for commit
+// messages, which should be the same each time for the same diff.
+const commitTemperature = 0
+
+// defaultContextCommits is how many recent subjects are shown as examples.
+const defaultContextCommits = 5
+
+// commitOptions holds the flags for the commit command.
+type commitOptions struct {
+	ai         aiOptions
+	dryRun     bool
+	maxTokens  int
+	candidates int
+	edit       bool
+	issue      string
+	signOff    bool
+	amend      bool
+	style      string
+	scope      string
+	lang       string
+	subjectMax int
+	// contextCommits is how many recent commit subjects to include as
+	// style examples.
+	contextCommits int
+	secrets        secretOptions
+	exclude        []string
+	anonymize      bool
+	// recurseSubmodules describes the changes inside changed submodules
+	// rather than just the commits they moved by.
+	recurseSubmodules bool
+	// diffAlgorithm is passed to git diff; "" uses git's configured one.
+	diffAlgorithm string
+	// includeUntracked sends the contents of untracked files as context.
+	includeUntracked bool
+	untracked        string
+	// paths limits the diff sent to the AI; everything staged is still
+	// committed.
+	paths []string
+	// all commits changes to tracked files whether or not they are
+	// staged, like git commit -a.
+	all bool
+	// noInteractive keeps an empty index an error instead of offering
+	// files to stage.
+	noInteractive bool
+	// fromStdin describes a diff read from stdin instead of the staged
+	// changes, and only prints the message.
+	fromStdin bool
+	// quiet suppresses progress messages, so that with --dry-run only the
+	// message is printed.
+	quiet bool
+	// copy puts the message printed by --dry-run on the clipboard too.
+	copy bool
+	// stats reports the token usage and latency of the AI requests.
+	stats bool
+	// bodyOnly keeps the subject of the commit being amended and only
+	// regenerates its body.
+	bodyOnly bool
+	out      output.OutputOptions
+	// summarizeLarge has a diff over maxTokens summarized in parts of at
+	// most chunkTokens, instead of truncated.
+	summarizeLarge bool
+	chunkTokens    int
+
+	// template is the repository's commit message template, if any.
+	template string
+	// promptFile is the --prompt-template file, and promptTemplate the
+	// parsed template that builds the prompt.
+	promptFile     string
+	promptTemplate *template.Template
+	// printPrompt prints the prompt template instead of committing.
+	printPrompt bool
+	// showPrompt prints the prompt that would be sent instead of sending
+	// it.
+	showPrompt bool
+	// signOffLine is the Signed-off-by trailer when --sign-off is set.
+	signOffLine string
+	// recent holds the recent commit subjects used as style examples.
+	recent []string
+	// scopeHint is the scope suggested by the changed paths when --scope
+	// is not set.
+	scopeHint string
+	// subject and trailers are kept from the amended commit with
+	// --amend-body-only.
+	subject  string
+	trailers string
+	// summarized is set when the diff given to prompt is summaries of its
+	// parts.
+	summarized bool
+}
+
+func newCommitCmd() *cobra.Command {
+	var opts commitOptions
+
+	cmd := &cobra.Command{
+		Use:   "commit [path...]",
+		Short: "Generate AI commit message",
+		Long: `Generate a commit message based on staged changes.
+
+This command runs 'git diff --cached' and sends the diff to an AI model
+to generate a meaningful commit message.
+
+With --candidates N, the AI suggests N messages to choose from.
+With --edit, the chosen message is opened in $EDITOR before committing.
+
+A diff over --max-tokens is truncated, keeping the most changed files.
+With --summarize-large it is instead split by file into parts of at most
+--chunk-tokens, each part is summarized by the AI, and the message is
+written from the summaries, so that every file is taken into account.
+
+If the repository has a commit template (commit.template or .gitmessage),
+the AI is asked to fill in its sections.
+
+An issue key found in the branch name (e.g. JIRA-1234 in
+feature/JIRA-1234-add-thing) is added as a "Refs:" footer. Set the
+pattern with issue-pattern in the config file, or use --issue.
+
+When nothing is staged but files have changed, commit lists them and asks
+which to stage. This only happens when stdin is a terminal; with
+--no-interactive, --dry-run, or when stdin is not a terminal, an empty
+index is an error.
+
+With --all (-a), modified and deleted tracked files are committed whether
+or not they are staged, as with 'git commit -a', and the message
+describes them too. Untracked files are not added.
+
+With --amend, the message is regenerated from the last commit plus any
+staged changes and the last commit is amended. --amend-body-only keeps the
+last commit's subject, and any trailers such as Signed-off-by, and only
+asks for a new body.
+
+--style selects the subject format: conventional (feat:, fix:, ...),
+plain imperative subjects, or gitmoji. The default is commit-format in
+the config file.
+
+With the conventional style, a scope is suggested from the directory the
+changed files share (internal/cmd gives feat(cmd): ...), and left out when
+they span several top-level directories. --scope sets the scope instead.
+
+Generated messages are checked before they are shown: the subject must
+fit in --subject-max characters (default subject-max in the config file,
+or 72) and be followed by a blank line, and conventional messages need a
+known type. A message that fails is sent back to the AI once with the
+problems to fix. If the subject is still too long it is cut at a word
+boundary; a message that breaks the other rules is rejected.
+
+--lang writes the message in another language, such as fr or German;
+conventional type prefixes stay in English.
+
+The subjects of the last --context-commits commits are included as
+examples so the message matches the repository's existing style.
+
+Before anything is sent, the diff is scanned for likely secrets such as
+API keys and private keys; if any are found you are asked whether to
+continue. --no-send-secrets aborts instead, and --force skips the scan.
+
+Paths given as arguments limit the diff sent to the AI to those files or
+directories, so the message describes only them. This only affects the
+prompt: everything staged is still committed. Each path must exist or be
+known to git.
+
+--exclude leaves files matching a glob, such as lockfiles or generated
+code, out of the diff sent to the AI. Excluded files are still committed;
+they are just not described.
+
+A changed submodule is described by the subjects of the commits it moved
+by; --recurse-submodules sends the diff of the files inside it instead.
+
+--include-untracked sends the contents of untracked files that .gitignore
+does not exclude as context, so the message can take new files into
+account before they are staged. They are still not committed. Binary files
+are left out, files over 256 KiB are only named, and the files get at most
+a quarter of --max-tokens.
+
+--diff-algorithm (minimal, patience, or histogram) picks how git diff
+matches lines, which can make the diff sent to the AI easier to follow;
+by default git's diff.algorithm setting applies.
+
+--anonymize replaces file paths, identifiers, and string literals in the
+diff, and in the subjects of submodule commits, with placeholders before
+it is sent, and leaves out the recent commit subjects. This is best-effort: the structure of the change is
+still visible, and the message will be less specific.
+
+--prompt-template replaces the built-in prompt with a Go text/template
+file, defaulting to prompt-template in the config file. Templates can use
+{{.Diff}}, {{.RecentCommits}}, {{.Scope}}, {{.ScopeHint}}, {{.Lang}},
+{{.Style}}, {{.StyleRules}}, {{.SubjectMax}}, {{.Template}},
+{{.Candidates}}, and {{.Delimiter}}, and must include the diff.
+--print-prompt-template prints the template in effect, as a starting point.
+--print-prompt prints the prompt built from it for the changes, with the
+provider and model it would go to, and exits without asking the AI; with
+--output json, as JSON. It cannot show the prompt for a diff that
+--summarize-large would summarize, since that takes AI requests.
+
+--yes (-y) commits the generated message, or the first of --candidates,
+without asking, for unattended use; --dry-run still takes precedence and
+commits nothing.
+
+Without a terminal on stdin, as in CI, nothing is asked: the message is
+committed only with --yes (which uses the first suggestion), large
+requests need --yes, and a diff with possible secrets is not sent without
+--force. Progress messages are left out when stdout is not a terminal.
+
+--from-stdin reads a unified diff, such as a patch file, from stdin
+instead of running git diff, and prints a message for it without
+committing, as with --dry-run; it works outside a git repository too. It
+cannot be combined with the options that read the repository's changes,
+such as --all, --amend, paths, and --include-untracked.
+
+--dry-run --quiet prints only the message, for use in scripts and git
+hooks (see 'arc-ai hook install'). --dry-run --output json prints the
+message's subject, body, and conventional type and scope as JSON, or a
+list of them with --candidates.
+
+--stats prints the tokens sent and received and the time spent waiting
+for the AI to stderr, totalled over every request the message took, or
+adds them under stats to a single message printed as JSON.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.paths = args
+			return opts.run(cmd)
+		},
+	}
+
+	opts.ai.addFlags(cmd)
+	opts.ai.setDefaultTemperature(cmd, commitTemperature)
+	cmd.Flags().IntVar(&opts.maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the diff sent to the AI")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show message without committing")
+	cmd.Flags().BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress progress output, so --dry-run prints only the message")
+	cmd.Flags().BoolVar(&opts.copy, "copy", false, "With --dry-run, also copy the message (the first of --candidates) to the system clipboard")
+	cmd.Flags().BoolVar(&opts.stats, "stats", false, "Report token usage and latency on stderr, or under stats with --output json")
+	cmd.Flags().IntVar(&opts.candidates, "candidates", 1, "Number of candidate messages to generate")
+	cmd.Flags().BoolVar(&opts.edit, "edit", false, "Edit the message in $EDITOR before committing")
+	cmd.Flags().StringVar(&opts.issue, "issue", "", "Issue key for the Refs: footer (default: detected from the branch name)")
+	cmd.Flags().BoolVarP(&opts.signOff, "sign-off", "s", false, "Add a Signed-off-by trailer")
+	cmd.Flags().BoolVarP(&opts.all, "all", "a", false, "Commit all changes to tracked files, staged or not, like git commit -a")
+	cmd.Flags().BoolVar(&opts.noInteractive, "no-interactive", false, "Fail instead of offering files to stage when nothing is staged")
+	cmd.Flags().BoolVar(&opts.fromStdin, "from-stdin", false, "Describe a unified diff read from stdin and print the message, without committing")
+	cmd.Flags().BoolVar(&opts.amend, "amend", false, "Regenerate the message for the last commit and amend it")
+	cmd.Flags().BoolVar(&opts.bodyOnly, "amend-body-only", false, "Amend the last commit with a new body, keeping its subject")
+	cmd.Flags().StringVar(&opts.style, "style", styleConventional, "Commit message style: "+strings.Join(styleNames(), ", "))
+	cmd.Flags().StringVar(&opts.scope, "scope", "", "Conventional commit scope (default: detected from the changed paths)")
+	cmd.Flags().StringVar(&opts.lang, "lang", defaultLang, "Language for the message, as a name or BCP-47 tag")
+	cmd.Flags().IntVar(&opts.subjectMax, "subject-max", defaultSubjectMax, "Longest subject line allowed, in characters")
+	cmd.Flags().StringVar(&opts.promptFile, "prompt-template", "", "Go text/template file for the prompt (default: built in)")
+	cmd.Flags().BoolVar(&opts.printPrompt, "print-prompt-template", false, "Print the prompt template in effect and exit")
+	cmd.Flags().BoolVar(&opts.showPrompt, "print-prompt", false, "Print the provider, model, and prompt that would be sent, and exit")
+	cmd.Flags().BoolVar(&opts.summarizeLarge, "summarize-large", false, "Summarize a diff over --max-tokens in parts instead of truncating it")
+	cmd.Flags().IntVar(&opts.chunkTokens, "chunk-tokens", defaultChunkTokens, "Largest part of the diff summarized at once with --summarize-large")
+	cmd.Flags().IntVar(&opts.contextCommits, "context-commits", defaultContextCommits, "Recent commit subjects to include as style examples (0 to disable)")
+	opts.secrets.addFlags(cmd)
+	cmd.Flags().BoolVar(&opts.anonymize, "anonymize", false, "Hide paths, identifiers, and strings in the diff sent to the AI (best-effort)")
+	cmd.Flags().StringArrayVar(&opts.exclude, "exclude", nil, "Glob of paths to leave out of the diff sent to the AI (repeatable)")
+	cmd.Flags().BoolVar(&opts.recurseSubmodules, "recurse-submodules", false, "Include the changes inside changed submodules in the diff")
+	cmd.Flags().BoolVar(&opts.includeUntracked, "include-untracked", false, "Send the contents of untracked files, not ignored by .gitignore, as context")
+	cmd.Flags().StringVar(&opts.diffAlgorithm, "diff-algorithm", "", "Diff algorithm for the diff sent to the AI: "+strings.Join(diffAlgorithms, ", ")+" (default: git's diff.algorithm)")
+	_ = cmd.RegisterFlagCompletionFunc("diff-algorithm", cobra.FixedCompletions(diffAlgorithms, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("style", cobra.FixedCompletions(styleNames(), cobra.ShellCompDirectiveNoFileComp))
+	opts.out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+func (o *commitOptions) run(cmd *cobra.Command) error {
+	if err := o.out.Resolve(); err != nil {
+		return err
+	}
+	if o.fromStdin {
+		if err := o.checkFromStdin(cmd); err != nil {
+			return err
+		}
+		// There is nothing to commit the message to
+		o.dryRun = true
+	}
+	if o.copy && !o.dryRun {
+		return fmt.Errorf("--copy requires --dry-run")
+	}
+	if o.out.Is(output.OutputJSON) {
+		if !o.dryRun && !o.showPrompt {
+			return fmt.Errorf("--output json requires --dry-run or --print-prompt")
+		}
+		// Progress lines would corrupt the JSON on stdout
+		o.quiet = true
+	}
+	if o.showPrompt {
+		// Or be mistaken for part of the prompt
+		o.quiet = true
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	o.ai.resolve(cmd, cfg)
+	if o.stats {
+		o.ai.stats = &requestStats{}
+		defer o.ai.stats.print(os.Stderr)
+	}
+	switch {
+	case o.quiet:
+		o.ai.progress = ""
+	case o.ai.progress != "":
+		o.ai.progress = "Generating commit message"
+	}
+	if !cmd.Flags().Changed("max-tokens") {
+		o.maxTokens = cfg.MaxTokens
+	}
+	if !cmd.Flags().Changed("style") {
+		o.style = cfg.CommitFormat
+	}
+	if _, err := findStyle(o.style); err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("subject-max") {
+		o.subjectMax = cfg.SubjectMax
+	}
+	if o.subjectMax < 1 {
+		return fmt.Errorf("--subject-max must be at least 1")
+	}
+	if o.chunkTokens < 1 {
+		return fmt.Errorf("--chunk-tokens must be at least 1")
+	}
+
+	if !cmd.Flags().Changed("prompt-template") {
+		o.promptFile = cfg.PromptTemplate
+	}
+	text, err := readPromptTemplate(o.promptFile)
+	if err != nil {
+		return err
+	}
+	if o.printPrompt {
+		fmt.Print(text)
+		return nil
+	}
+	name := o.promptFile
+	if name == "" {
+		name = "commit"
+	}
+	o.promptTemplate, err = parsePromptTemplate(name, text)
+	if err != nil {
+		return err
+	}
+
+	if err := checkDiffAlgorithm(o.diffAlgorithm); err != nil {
+		return err
+	}
+	if o.includeUntracked && o.anonymize {
+		// Their paths and contents would go unhidden
+		return fmt.Errorf("--include-untracked cannot be combined with --anonymize")
+	}
+	if o.candidates < 1 {
+		return fmt.Errorf("--candidates must be at least 1")
+	}
+	if o.bodyOnly {
+		if o.candidates > 1 {
+			return fmt.Errorf("--amend-body-only cannot be combined with --candidates")
+		}
+		if cmd.Flags().Changed("prompt-template") {
+			return fmt.Errorf("--prompt-template does not apply to --amend-body-only")
+		}
+		if o.includeUntracked {
+			return fmt.Errorf("--include-untracked does not apply to --amend-body-only")
+		}
+		o.amend = true
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// A diff from stdin needs no repository, but uses one it is run in for
+	// the commit template and recent subjects
+	inRepo := true
+	if err := requireRepo(ctx); err != nil {
+		if !o.fromStdin {
+			return err
+		}
+		inRepo = false
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var diff string
+	if o.fromStdin {
+		diff, err = stdinDiff(reader)
+	} else {
+		diff, err = o.diff(ctx)
+	}
+	if errors.Is(err, ErrNoStagedChanges) && o.canPick() {
+		var ok bool
+		diff, ok, err = o.pickAndStage(ctx, reader)
+		if err == nil && !ok {
+			return &Error{Kind: KindCancelled, Msg: "commit cancelled"}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.includeUntracked {
+		o.untracked, err = untrackedContext(ctx, o.paths, o.exclude, o.maxTokens/untrackedShare)
+		if err != nil {
+			return err
+		}
+		// The diff gets what the files leave of the budget
+		if o.maxTokens > 0 {
+			o.maxTokens = max(o.maxTokens-estimateTokens(o.untracked), 1)
+		}
+	}
+
+	// --print-prompt sends nothing, so there is nothing to hold back
+	if !o.showPrompt {
+		if ok, err := o.secrets.check(reader, diff+o.untracked); !ok {
+			return err
+		}
+	}
+
+	// The directory name would reveal what --anonymize hides
+	if o.style == styleConventional && o.scope == "" && !o.anonymize {
+		o.scopeHint = detectScope(diff)
+	}
+
+	if o.anonymize {
+		diff = anonymizeDiff(diff)
+	}
+	summarize := o.summarizeLarge && o.maxTokens > 0 && estimateTokens(diff) > o.maxTokens
+	if !summarize {
+		diff = truncateDiff(diff, o.maxTokens)
+	}
+
+	if inRepo {
+		o.template, err = commitTemplate(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.issue == "" {
+		o.issue, err = detectIssue(ctx, cfg.IssuePattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.signOff {
+		o.signOffLine, err = signOffTrailer(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Commit subjects can identify the project as well as its code can
+	if o.contextCommits > 0 && !o.anonymize {
+		// The commit being amended is not an example of existing style
+		skip := 0
+		if o.amend {
+			skip = 1
+		}
+		o.recent, err = recentSubjects(ctx, o.contextCommits, skip)
+		if err != nil {
+			return err
+		}
+	}
+
+	var head string
+	if o.amend {
+		head, err = headSummary(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if o.bodyOnly {
+		message, err := headMessage(ctx)
+		if err != nil {
+			return err
+		}
+		var body string
+		o.subject, body = splitMessage(message)
+		_, o.trailers = splitTrailers(body)
+	}
+
+	// Last, so that nothing after it can fail once the parts are paid for
+	if summarize {
+		if o.showPrompt {
+			return fmt.Errorf("--print-prompt cannot show the prompt for a diff that --summarize-large would summarize, since that asks the AI; leave out --summarize-large to see it truncated")
+		}
+		var ok bool
+		diff, ok, err = o.summarizeDiff(ctx, reader, diff)
+		if !ok {
+			return err
+		}
+	}
+
+	prompt, err := o.prompt(diff)
+	if err != nil {
+		return err
+	}
+	if o.showPrompt {
+		preview := previewRequest(o.ai.request(prompt))
+		if o.out.Is(output.OutputJSON) {
+			return output.JSON(preview)
+		}
+		preview.print(os.Stdout)
+		return nil
+	}
+	if ok, err := o.ai.preflight(reader, prompt); !ok {
+		return err
+	}
+
+	var message string
+	for message == "" {
+		if o.ai.progress == "" {
+			// Without the spinner, which says the same
+			o.status("Generating commit message...")
+		}
+
+		candidates, err := o.generate(ctx, diff)
+		if err != nil {
+			return err
+		}
+
+		if o.dryRun && !o.edit {
+			if o.copy {
+				copyResponse(ctx, o.ai.log, candidates[0])
+			}
+			if o.out.Is(output.OutputJSON) {
+				return writeMessageJSON(candidates, o.ai.stats)
+			}
+			if o.quiet {
+				fmt.Println(candidates[0])
+				return nil
+			}
+			if o.amend {
+				fmt.Printf("\nWould amend %s.\n", head)
+			}
+			printCandidates(candidates)
+			return nil
+		}
+
+		// Opening the editor is confirmation enough for a single message
+		if o.edit && len(candidates) == 1 {
+			message = candidates[0]
+			break
+		}
+
+		if o.ai.yes {
+			message = candidates[0]
+			break
+		}
+		if !stdinIsTerminal() {
+			return fmt.Errorf("no terminal to confirm the commit message; pass --yes to use the first suggestion, or --dry-run to print it")
+		}
+
+		if o.amend {
+			fmt.Printf("\nWarning: this rewrites the last commit (%s).\n", head)
+		}
+
+		var ok bool
+		message, ok = chooseCandidate(reader, candidates)
+		if !ok {
+			return &Error{Kind: KindCancelled, Msg: "commit cancelled"}
+		}
+		// The same temperature as before would likely repeat the message
+		o.ai.varyTemperature()
+	}
+
+	if o.edit {
+		message, err = editMessage(ctx, message)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.dryRun {
+		if o.copy {
+			copyResponse(ctx, o.ai.log, message)
+		}
+		if o.out.Is(output.OutputJSON) {
+			return writeMessageJSON([]string{message}, o.ai.stats)
+		}
+		if o.quiet {
+			fmt.Println(message)
+			return nil
+		}
+		if o.amend {
+			fmt.Printf("\nWould amend %s with message:\n%s\n", head, message)
+			return nil
+		}
+		fmt.Printf("\nCommit message:\n%s\n", message)
+		return nil
+	}
+
+	// Create the commit
+	commitArgs := []string{"commit", "-m", message}
+	if o.amend {
+		commitArgs = append(commitArgs, "--amend")
+	}
+	if o.all {
+		// git stages the tracked changes as it commits, so nothing is
+		// staged if the commit is cancelled
+		commitArgs = append(commitArgs, "--all")
+	}
+	// git, and any hooks it runs, get Ctrl-C from the terminal and clean up
+	// after themselves
+	commitCmd := exec.CommandContext(context.WithoutCancel(ctx), "git", commitArgs...)
+	commitCmd.Stdout = os.Stdout
+	commitCmd.Stderr = os.Stderr
+	defer inForeground()()
+
+	if err := commitCmd.Run(); err != nil {
+		return fmt.Errorf("git commit failed: %w", err)
+	}
+
+	return nil
+}
+
+// writeMessageJSON prints the parts of a single message as a JSON object,
+// with any --stats, or of several as a list, which leaves the stats to be
+// printed on stderr.
+func writeMessageJSON(messages []string, stats *requestStats) error {
+	if len(messages) == 1 {
+		return output.JSON(struct {
+			messageParts
+			Stats *statsReport `json:"stats,omitempty"`
+		}{parseMessage(messages[0]), stats.report()})
+	}
+	parts := make([]messageParts, len(messages))
+	for i, m := range messages {
+		parts[i] = parseMessage(m)
+	}
+	return output.JSON(parts)
+}
+
+// canPick reports whether an empty index can be filled by picking files
+// interactively: only for a plain commit run from a terminal, since
+// --dry-run should not change the index and scripts cannot answer.
+func (o *commitOptions) canPick() bool {
+	return !o.noInteractive && !o.dryRun && !o.amend && !o.all &&
+		len(o.paths) == 0 && len(o.exclude) == 0 && stdinIsTerminal()
+}
+
+// pickAndStage offers the files with unstaged changes for staging and
+// returns the staged diff, or ok false if the user staged nothing. With no
+// changes to offer it returns ErrNoStagedChanges.
+func (o *commitOptions) pickAndStage(ctx context.Context, reader *bufio.Reader) (diff string, ok bool, err error) {
+	changes, err := workingChanges(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	if len(changes) == 0 {
+		return "", false, ErrNoStagedChanges
+	}
+
+	paths, ok := pickChanges(reader, changes)
+	if !ok {
+		return "", false, nil
+	}
+	if err := stagePaths(ctx, paths); err != nil {
+		return "", false, err
+	}
+
+	diff, err = o.diff(ctx)
+	return diff, err == nil, err
+}
+
+// checkFromStdin returns an error if an option that reads the
+// repository's changes is combined with --from-stdin.
+func (o *commitOptions) checkFromStdin(cmd *cobra.Command) error {
+	for _, name := range []string{"all", "amend", "amend-body-only", "edit", "include-untracked", "recurse-submodules", "diff-algorithm", "exclude"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--from-stdin cannot be combined with --%s", name)
+		}
+	}
+	if len(o.paths) > 0 {
+		return fmt.Errorf("--from-stdin takes no paths")
+	}
+	if stdinIsTerminal() {
+		return fmt.Errorf("--from-stdin needs a diff piped to stdin")
+	}
+	return nil
+}
+
+// stdinDiff reads the diff for --from-stdin from reader. It fails unless
+// the text has at least one hunk or file header of a unified diff.
+func stdinDiff(reader *bufio.Reader) (string, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	diff := string(data)
+	if strings.TrimSpace(diff) == "" {
+		return "", &Error{Kind: KindNoChanges, Msg: "no diff on stdin"}
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		if hunkHeader.MatchString(line) || strings.HasPrefix(line, "diff --git ") {
+			return diff, nil
+		}
+	}
+	return "", fmt.Errorf("stdin does not contain a unified diff")
+}
+
+// status prints a progress or status line unless --quiet is set or stdout
+// is not a terminal.
+func (o *commitOptions) status(msg string) {
+	if !o.quiet && stdoutIsTerminal() {
+		fmt.Println(msg)
+	}
+}
+
+// diff returns the changes to describe: the staged diff, or with --amend
+// the last commit combined with the staged changes, limited to o.paths.
+// With --all, unstaged changes to tracked files count as staged.
+func (o *commitOptions) diff(ctx context.Context) (string, error) {
+	if err := checkPaths(ctx, o.paths); err != nil {
+		return "", err
+	}
+	format := diffFormat{recurseSubmodules: o.recurseSubmodules, algorithm: o.diffAlgorithm}
+
+	if o.amend {
+		diff, err := amendDiff(ctx, o.all, format, o.paths, o.exclude)
+		if err != nil {
+			return "", err
+		}
+		if len(diff) == 0 {
+			if len(o.paths) > 0 {
+				return "", &Error{Kind: KindNoChanges, Msg: "nothing to amend: the last commit has no changes in the given paths"}
+			}
+			return "", &Error{Kind: KindNoChanges, Msg: "nothing to amend: the last commit has no changes"}
+		}
+		return diff, nil
+	}
+
+	var diff string
+	var err error
+	if o.all {
+		diff, err = trackedDiff(ctx, format, o.paths, o.exclude)
+	} else {
+		diff, err = gitDiff(ctx, true, format, o.paths, o.exclude)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if len(diff) == 0 {
+		if o.all {
+			return "", &Error{Kind: KindNoChanges, Msg: "no changes to tracked files"}
+		}
+		if len(o.paths) > 0 {
+			return "", &Error{Kind: KindNoChanges, Msg: "no staged changes in the given paths"}
+		}
+		if len(o.exclude) > 0 {
+			return "", &Error{Kind: KindNoChanges, Msg: "no staged changes outside the excluded paths"}
+		}
+		return "", ErrNoStagedChanges
+	}
+	return diff, nil
+}
+
+// generate asks the AI for commit message candidates for diff.
+func (o *commitOptions) generate(ctx context.Context, diff string) ([]string, error) {
+	prompt, err := o.prompt(diff)
+	if err != nil {
+		return nil, err
+	}
+	response, err := askAI(ctx, o.ai.request(prompt))
+	if err != nil {
+		return nil, err
+	}
+
+	if o.bodyOnly {
+		return []string{o.finish(o.withBody(response))}, nil
+	}
+
+	candidates := []string{strings.TrimSpace(response)}
+	if o.candidates > 1 {
+		candidates = parseCandidates(response)
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("AI returned no commit messages")
+		}
+		if len(candidates) > o.candidates {
+			candidates = candidates[:o.candidates]
+		}
+	}
+
+	for i, c := range candidates {
+		candidates[i], err = o.lint(ctx, o.finish(c))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return candidates, nil
+}
+
+// lint checks a finished message with lintMessage. If it breaks the rules
+// the AI is asked once to fix it; a subject that is still too long is then
+// shortened, and the message is rejected if it breaks any other rule.
+func (o *commitOptions) lint(ctx context.Context, message string) (string, error) {
+	violations := lintMessage(message, o.style, o.subjectMax)
+	if len(violations) == 0 {
+		return message, nil
+	}
+
+	o.ai.log.Debug("generated message breaks the style rules; asking for a fix", "violations", len(violations))
+	prompt := fmt.Sprintf(`This git commit message breaks these rules:
+- %s
+
+Rewrite it to follow them, keeping its meaning.
+Respond with ONLY the commit message, no explanations.
+
+Message:
+%s`, strings.Join(violations, "\n- "), message)
+
+	response, err := askAI(ctx, o.ai.request(prompt))
+	if err != nil {
+		return "", err
+	}
+	message = o.finish(strings.TrimSpace(response))
+	// Better a slightly clipped subject than no message at all
+	message = shortenSubject(message, o.subjectMax)
+
+	if violations := lintMessage(message, o.style, o.subjectMax); len(violations) > 0 {
+		return "", fmt.Errorf("generated commit message does not follow the %s style:\n  - %s\n\n%s",
+			o.style, strings.Join(violations, "\n  - "), message)
+	}
+	return message, nil
+}
+
+// withBody builds the --amend-body-only message from the kept subject and
+// trailers and the body in response.
+func (o *commitOptions) withBody(response string) string {
+	body := unwrapCodeFence(response)
+	// Models sometimes repeat the subject they were given
+	if subject, rest := splitMessage(body); subject == o.subject {
+		body = rest
+	}
+	body, _ = splitTrailers(body)
+
+	message := joinMessage(o.subject, body)
+	for _, trailer := range strings.Split(o.trailers, "\n") {
+		if trailer != "" {
+			message = appendTrailer(message, trailer)
+		}
+	}
+	return message
+}
+
+// finish removes a code fence the model wrapped around a generated message,
+// normalizes its type prefix, and applies the scope and footers requested
+// by flags.
+func (o *commitOptions) finish(message string) string {
+	message = unwrapCodeFence(message)
+	// The subject kept by --amend-body-only is the user's, not generated
+	if !o.bodyOnly {
+		message = collapseTypePrefixes(message)
+	}
+	if o.scope != "" && o.style == styleConventional && !o.bodyOnly {
+		message = applyScope(message, o.scope)
+	}
+	if o.issue != "" {
+		message = appendTrailer(message, "Refs: "+o.issue)
+	}
+	if o.signOffLine != "" {
+		message = appendTrailer(message, o.signOffLine)
+	}
+	return message
+}
+
+// detectIssue finds an issue key matching pattern in the current branch
+// name. It returns "" if there is none.
+func detectIssue(ctx context.Context, pattern string) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid issue pattern: %w", err)
+	}
+
+	branch, err := currentBranch(ctx)
+	if err != nil {
+		// No branch yet (e.g. before the first commit)
+		return "", nil
+	}
+	return re.FindString(branch), nil
+}
+
+// prompt builds the commit message prompt for diff from the prompt
+// template.
+func (o *commitOptions) prompt(diff string) (string, error) {
+	if o.bodyOnly {
+		return o.bodyPrompt(diff), nil
+	}
+
+	// run has already validated the style
+	style, _ := findStyle(o.style)
+
+	data := commitPromptData{
+		Diff:          diff,
+		Summarized:    o.summarized,
+		RecentCommits: o.recent,
+		ScopeHint:     o.scopeHint,
+		Style:         o.style,
+		StyleRules:    style.rules,
+		SubjectMax:    o.subjectMax,
+		Template:      o.template,
+		Untracked:     o.untracked,
+		Candidates:    o.candidates,
+		Delimiter:     candidateDelimiter,
+	}
+	if o.style == styleConventional {
+		data.Scope = o.scope
+	}
+	if !isEnglish(o.lang) {
+		data.Lang = o.lang
+	}
+
+	var b strings.Builder
+	if err := o.promptTemplate.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("prompt template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// bodyPrompt asks for only the body of a commit message whose subject is
+// already written.
+func (o *commitOptions) bodyPrompt(diff string) string {
+	var rules string
+	if lang := languageRule("the body", o.lang); lang != "" {
+		rules = "\n" + lang
+	}
+	label := "Diff:"
+	if o.summarized {
+		label = "The diff is too large to include, so here are summaries of its parts:"
+	}
+
+	return fmt.Sprintf(`Write the body of a git commit message for the following diff.
+The subject line is already written and must not change:
+%s
+
+Explain what changed and why in a few short paragraphs or bullet points,
+wrapped at 72 characters.%s
+
+%s
+%s
+
+Respond with ONLY the body, without the subject line or explanations.`, o.subject, rules, label, diff)
+}
+
+// parseCandidates splits a multi-candidate response on candidateDelimiter
+// lines, dropping empty entries.
+func parseCandidates(response string) []string {
+	var candidates []string
+	var cur []string
+	flush := func() {
+		if msg := strings.TrimSpace(strings.Join(cur, "\n")); msg != "" {
+			candidates = append(candidates, msg)
+		}
+		cur = nil
+	}
+
+	for _, line := range strings.Split(response, "\n") {
+		if strings.TrimSpace(line) == candidateDelimiter {
+			flush()
+			continue
+		}
+		cur = append(cur, line)
+	}
+	flush()
+
+	return candidates
+}
+
+func printCandidates(candidates []string) {
+	if len(candidates) == 1 {
+		fmt.Printf("\nSuggested commit message:\n%s\n", candidates[0])
+		return
+	}
+
+	fmt.Println("\nSuggested commit messages:")
+	for i, c := range candidates {
+		fmt.Printf("\n[%d] %s\n", i+1, c)
+	}
+}
+
+// chooseCandidate asks the user to pick a message. It returns an empty
+// message with ok set to request regeneration, and ok false if the user
+// cancelled.
+func chooseCandidate(reader *bufio.Reader, candidates []string) (message string, ok bool) {
+	printCandidates(candidates)
+	fmt.Println()
+
+	if len(candidates) == 1 {
+		fmt.Print("Use this message? [Y/n]: ")
+
+		response, _ := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+
+		if response != "" && response != "y" && response != "yes" {
+			return "", false
+		}
+		return candidates[0], true
+	}
+
+	for {
+		fmt.Printf("Choose a message [1-%d], r to regenerate, q to cancel [1]: ", len(candidates))
+
+		response, err := reader.ReadString('\n')
+		response = strings.TrimSpace(strings.ToLower(response))
+
+		switch {
+		case response == "":
+			if err != nil {
+				return "", false
+			}
+			return candidates[0], true
+		case response == "r":
+			return "", true
+		case response == "q":
+			return "", false
+		}
+
+		if n, convErr := strconv.Atoi(response); convErr == nil && n >= 1 && n <= len(candidates) {
+			return candidates[n-1], true
+		}
+		if err != nil {
+			return "", false
+		}
+		fmt.Println("Invalid choice.")
+	}
+}
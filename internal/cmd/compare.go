@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// comparedAnswer is one model's answer in the --output json and yaml form
+// of ask with several --model flags.
+type comparedAnswer struct {
+	Response string `json:"response,omitempty" yaml:"response,omitempty"`
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+	// Model is the ID of the model that answered, when known, which can
+	// differ from the --model given, an alias.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// checkModels returns an error if the --model flags name a model twice.
+func checkModels(models []string) error {
+	seen := map[string]bool{}
+	for _, m := range models {
+		if seen[m] {
+			return fmt.Errorf("--model %s is given more than once", m)
+		}
+		seen[m] = true
+	}
+	return nil
+}
+
+// askModels sends req to each of models at once and returns their
+// responses and errors in the same order. Unlike --race, every request
+// runs to the end: one failing or answering first stops none of the
+// others.
+func askModels(ctx context.Context, req aiRequest, models []string) ([]ai.Response, []error) {
+	// One spinner for all of them, rather than one each
+	if req.Progress != "" {
+		s := startSpinner(ctx, os.Stderr, req.Progress)
+		defer s.Stop()
+		req.Progress = ""
+	}
+
+	resps := make([]ai.Response, len(models))
+	errs := make([]error, len(models))
+	var wg sync.WaitGroup
+	for i, model := range models {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := req
+			r.Model = model
+			resps[i], errs[i] = askAIResponse(ctx, r)
+		}()
+	}
+	wg.Wait()
+	return resps, errs
+}
+
+// comparedAnswers returns the answers of askModels by model, for
+// --output json and yaml.
+func comparedAnswers(models []string, resps []ai.Response, errs []error) map[string]comparedAnswer {
+	answers := make(map[string]comparedAnswer, len(models))
+	for i, m := range models {
+		if errs[i] != nil {
+			answers[m] = comparedAnswer{Error: errs[i].Error()}
+			continue
+		}
+		answers[m] = comparedAnswer{Response: resps[i].Text, Provider: resps[i].Provider, Model: resps[i].Model}
+	}
+	return answers
+}
+
+// modelsFailed returns an error if any of the models failed to answer,
+// once the answers and errors have been shown.
+func modelsFailed(errs []error) error {
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	switch failed {
+	case 0:
+		return nil
+	case len(errs):
+		return &Error{Kind: KindProviderFailed, Msg: "every model failed"}
+	}
+	return &Error{Kind: KindProviderFailed, Msg: fmt.Sprintf("%d of %d models failed", failed, len(errs))}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// completionTimeout bounds dynamic completions so the shell never hangs.
+const completionTimeout = 2 * time.Second
+
+// outputFormats lists the values accepted by --output.
+var outputFormats = []string{"table", "json"}
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate shell completion script",
+		Long: `Generate a shell completion script for arc-ai.
+
+  bash:       source <(arc-ai completion bash)
+  zsh:        arc-ai completion zsh > "${fpath[1]}/_arc-ai"
+  fish:       arc-ai completion fish > ~/.config/fish/completions/arc-ai.fish
+  powershell: arc-ai completion powershell | Out-String | Invoke-Expression`,
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			w := cmd.OutOrStdout()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(w, true)
+			case "zsh":
+				return root.GenZshCompletion(w)
+			case "fish":
+				return root.GenFishCompletion(w, true)
+			case "powershell":
+				return root.GenPowerShellCompletionWithDesc(w)
+			}
+			return fmt.Errorf("unsupported shell %q", args[0])
+		},
+	}
+
+	return cmd
+}
+
+// registerOutputCompletion offers the --output formats as completions,
+// plus any extra formats the command handles itself.
+func registerOutputCompletion(cmd *cobra.Command, extra ...string) {
+	formats := append(append([]string{}, outputFormats...), extra...)
+	_ = cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func completeProviders(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if cfg, err := LoadConfig(); err == nil {
+		_ = registerCommandProviders(cfg)
+	}
+	return providerNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeModels lists models from the provider that the command would use.
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	name, _ := cmd.Flags().GetString("provider")
+	order, _ := cmd.Flags().GetStringSlice("provider-order")
+	var aliases map[string]string
+	if cfg, err := LoadConfig(); err == nil {
+		_ = registerCommandProviders(cfg)
+		aliases = cfg.ModelAliases
+		if !cmd.Flags().Changed("provider") {
+			name = cfg.Provider
+		}
+		if !cmd.Flags().Changed("provider-order") {
+			order = cfg.Providers
+		}
+	}
+
+	provider, err := ai.Select(name, order)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	// Descriptions after a tab are shown by shells that support them
+	var completions []string
+	seen := map[string]bool{}
+	for alias := range aliases {
+		name, short, scoped := strings.Cut(alias, "/")
+		if !scoped {
+			short = alias
+		} else if name != provider {
+			continue
+		}
+		if !seen[short] {
+			seen[short] = true
+			completions = append(completions, short+"\talias for "+ai.ResolveModel(provider, short, aliases))
+		}
+	}
+	sort.Strings(completions)
+
+	models, err := ai.Models(ctx, provider)
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, m := range models {
+		c := m.ID
+		if m.Description != "" {
+			c += "\t" + m.Description
+		}
+		completions = append(completions, c)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-ai/ai"
+	"gopkg.in/yaml.v3"
+)
+
+// localConfigName is the repo-local config file, found by walking up from
+// the working directory to the repository root.
+const localConfigName = ".arc-ai.yaml"
+
+// defaultIssuePattern matches Jira-style issue keys such as ABC-123.
+const defaultIssuePattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+// Config holds defaults for arc-ai commands.
+//
+// Values are resolved in increasing order of precedence: built-in defaults,
+// ARC_AI_* environment variables, the global config file, the repo-local
+// config file, and finally command-line flags (applied by each command).
+type Config struct {
+	// Model is used with every provider; Models sets one per provider.
+	Model string `yaml:"model,omitempty"`
+	// Models are default models by provider name, used when Model is not
+	// set, so that each provider the auto provider falls back to gets a
+	// name it accepts.
+	Models map[string]string `yaml:"models,omitempty"`
+	// ModelAliases map short names for --model to model IDs: "fast" for
+	// every provider, or "openai/fast" for one.
+	ModelAliases map[string]string `yaml:"model-aliases,omitempty"`
+	// LargerModels map a model to one with a larger context window to
+	// retry with when a prompt is too long for it.
+	LargerModels map[string]string `yaml:"larger-models,omitempty"`
+	Provider     string            `yaml:"provider,omitempty"`
+	// BaseURLs replace the endpoints of provider APIs, by provider name,
+	// to go through a gateway or reach an Azure OpenAI deployment. Only
+	// the global config file can set them, since requests to them carry
+	// the diff and the API key.
+	BaseURLs map[string]string `yaml:"base-urls,omitempty"`
+	// Providers is the order in which the auto provider tries providers.
+	Providers    []string `yaml:"providers,omitempty"`
+	MaxTokens    int      `yaml:"max-tokens,omitempty"`
+	CommitFormat string   `yaml:"commit-format,omitempty"`
+	// SubjectMax is the longest commit subject line, in characters.
+	SubjectMax int `yaml:"subject-max,omitempty"`
+	// PromptTemplate is a commit prompt template file. A relative path is
+	// relative to the config file that sets it.
+	PromptTemplate string `yaml:"prompt-template,omitempty"`
+	// IssuePattern is a regular expression matching issue keys in branch
+	// names, e.g. JIRA-1234 in feature/JIRA-1234-add-thing.
+	IssuePattern string `yaml:"issue-pattern,omitempty"`
+	// System is the default system prompt for ask.
+	System string `yaml:"system,omitempty"`
+	// ConfirmTokens is the estimated request size, in tokens, above which
+	// commands ask before sending; 0 never asks.
+	ConfirmTokens int `yaml:"confirm-tokens,omitempty"`
+	// Rates are input prices in USD per million tokens, by model, used to
+	// estimate the cost of a request.
+	Rates map[string]float64 `yaml:"rates,omitempty"`
+	// CACert is a PEM file of extra certificate authorities to trust for
+	// provider APIs, relative to the config file that sets it. Only the
+	// global config file can set it.
+	CACert string `yaml:"ca-cert,omitempty"`
+	// InsecureSkipVerify disables certificate checks for provider APIs.
+	// Only the global config file can set it.
+	InsecureSkipVerify bool `yaml:"insecure-skip-verify,omitempty"`
+	// AuditLog is a JSONL file that a record of each AI request is
+	// appended to, relative to the config file that sets it. Only the
+	// global config file can set it, so that a repository cannot make
+	// arc-ai write to other files.
+	AuditLog string `yaml:"audit-log,omitempty"`
+	// AuditFull records full prompts in the audit log, not just hashes.
+	AuditFull bool `yaml:"audit-full,omitempty"`
+	// LocalOnly refuses hosted providers. Once a config file sets it, a
+	// later one cannot unset it.
+	LocalOnly bool `yaml:"local-only,omitempty"`
+	// CommandProviders are extra providers, by name, that run a command
+	// for each request. Only the global config file can set them, so that
+	// a repository cannot make arc-ai run its commands.
+	CommandProviders map[string]CommandProvider `yaml:"command-providers,omitempty"`
+}
+
+// CommandProvider configures a provider that runs a command (see
+// ai.CommandProvider).
+type CommandProvider struct {
+	// Command is the command line, with {{.Prompt}} and {{.Model}} in its
+	// arguments replaced for each request.
+	Command string `yaml:"command"`
+	// Model is the provider's default model.
+	Model string `yaml:"model,omitempty"`
+	// Local marks the command as running the model on this machine, for
+	// local-only.
+	Local bool `yaml:"local,omitempty"`
+}
+
+// defaultConfig returns the built-in defaults.
+func defaultConfig() *Config {
+	return &Config{
+		Provider:      providerAuto,
+		MaxTokens:     defaultMaxTokens,
+		CommitFormat:  styleConventional,
+		SubjectMax:    defaultSubjectMax,
+		IssuePattern:  defaultIssuePattern,
+		ConfirmTokens: defaultConfirmTokens,
+	}
+}
+
+// LoadConfig resolves the effective configuration from defaults, environment
+// variables, and config files.
+func LoadConfig() (*Config, error) {
+	return loadConfig("", nil)
+}
+
+// loadConfig is LoadConfig with data, if it is not nil, read as the
+// contents of the config file at path, the global or the local one, to
+// check a change before it is written.
+func loadConfig(path string, data []byte) (*Config, error) {
+	cfg := defaultConfig()
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	// The global config comes first, then any local one
+	type configFile struct {
+		path   string
+		global bool
+	}
+	var files []configFile
+	global, err := globalConfigPath()
+	if err == nil {
+		files = append(files, configFile{global, true})
+	}
+	local := findLocalConfig()
+	if data != nil && path != global {
+		local = path
+	}
+	if local != "" {
+		files = append(files, configFile{local, false})
+	}
+
+	for _, f := range files {
+		var err error
+		if data != nil && f.path == path {
+			err = cfg.applyData(f.path, data, f.global)
+		} else {
+			err = cfg.applyFile(f.path, f.global)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// globalConfigPath returns ~/.config/arc-ai/config.yaml, honoring
+// XDG_CONFIG_HOME.
+func globalConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "arc-ai", "config.yaml"), nil
+}
+
+// findLocalConfig looks for .arc-ai.yaml in the working directory and its
+// parents, stopping at the repository root.
+func findLocalConfig() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, localConfigName)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func (c *Config) applyEnv() error {
+	if v := os.Getenv("ARC_AI_MODEL"); v != "" {
+		c.Model = v
+	}
+	if v := os.Getenv("ARC_AI_PROVIDER"); v != "" {
+		c.Provider = v
+	}
+	if v := os.Getenv("ARC_AI_MAX_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("ARC_AI_MAX_TOKENS: %w", err)
+		}
+		c.MaxTokens = n
+	}
+	if v := os.Getenv("ARC_AI_CONFIRM_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("ARC_AI_CONFIRM_TOKENS: %w", err)
+		}
+		c.ConfirmTokens = n
+	}
+	if v := os.Getenv("ARC_AI_COMMIT_FORMAT"); v != "" {
+		c.CommitFormat = v
+	}
+	if v := os.Getenv("ARC_AI_SUBJECT_MAX"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("ARC_AI_SUBJECT_MAX: %w", err)
+		}
+		c.SubjectMax = n
+	}
+	if v := os.Getenv("ARC_AI_ISSUE_PATTERN"); v != "" {
+		c.IssuePattern = v
+	}
+	if v := os.Getenv("ARC_AI_SYSTEM"); v != "" {
+		c.System = v
+	}
+	if v := os.Getenv("ARC_AI_CA_CERT"); v != "" {
+		c.CACert = v
+	}
+	if v := os.Getenv("ARC_AI_AUDIT_LOG"); v != "" {
+		c.AuditLog = v
+	}
+	return nil
+}
+
+// applyFile overlays the values set in the YAML file at path, which is the
+// global config file if global is set. A missing file is not an error.
+func (c *Config) applyFile(path string, global bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+	return c.applyData(path, data, global)
+}
+
+// applyData overlays the values set in data, the contents of the config
+// file at path.
+func (c *Config) applyData(path string, data []byte, global bool) error {
+	// Decoding onto c keeps values for keys the file does not set
+	prompt, caCert, audit, localOnly := c.PromptTemplate, c.CACert, c.AuditLog, c.LocalOnly
+	commands, baseURLs, insecure := c.CommandProviders, c.BaseURLs, c.InsecureSkipVerify
+	if !global {
+		c.CommandProviders, c.BaseURLs = nil, nil
+		c.CACert, c.InsecureSkipVerify, c.AuditLog = "", false, ""
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if !global {
+		switch {
+		case len(c.CommandProviders) > 0:
+			return globalOnly(path, "command-providers")
+		case len(c.BaseURLs) > 0:
+			return globalOnly(path, "base-urls")
+		case c.CACert != "":
+			return globalOnly(path, "ca-cert")
+		case c.InsecureSkipVerify:
+			return globalOnly(path, "insecure-skip-verify")
+		case c.AuditLog != "":
+			return globalOnly(path, "audit-log")
+		}
+		c.CommandProviders, c.BaseURLs = commands, baseURLs
+		c.CACert, c.InsecureSkipVerify, c.AuditLog = caCert, insecure, audit
+	}
+	// A repository's config must not lift the user's guardrail
+	c.LocalOnly = c.LocalOnly || localOnly
+	if c.PromptTemplate != prompt && c.PromptTemplate != "" && !filepath.IsAbs(c.PromptTemplate) {
+		c.PromptTemplate = filepath.Join(filepath.Dir(path), c.PromptTemplate)
+	}
+	if c.CACert != caCert && c.CACert != "" && !filepath.IsAbs(c.CACert) {
+		c.CACert = filepath.Join(filepath.Dir(path), c.CACert)
+	}
+	if c.AuditLog != audit && c.AuditLog != "" && !filepath.IsAbs(c.AuditLog) {
+		c.AuditLog = filepath.Join(filepath.Dir(path), c.AuditLog)
+	}
+	return nil
+}
+
+// globalOnly is the error for a repo-local config file at path that sets
+// key, which only the global config file can set.
+func globalOnly(path, key string) error {
+	return fmt.Errorf("config %s: %s can only be set in the global config file", path, key)
+}
+
+func (c *Config) validate() error {
+	for name, p := range c.CommandProviders {
+		if err := ai.CheckCommand(name, p.aiProvider()); err != nil {
+			return fmt.Errorf("config: command-providers: %w", err)
+		}
+	}
+	if c.Provider != providerAuto && !c.knownProvider(c.Provider) {
+		return fmt.Errorf("config: unknown provider %q", c.Provider)
+	}
+	for _, name := range c.Providers {
+		if !c.knownProvider(name) {
+			return fmt.Errorf("config: providers: unknown provider %q (valid: %s)", name, strings.Join(c.providerNames(), ", "))
+		}
+	}
+	for name := range c.Models {
+		if !c.knownProvider(name) {
+			return fmt.Errorf("config: models: unknown provider %q (valid: %s)", name, strings.Join(c.providerNames(), ", "))
+		}
+	}
+	for alias, id := range c.ModelAliases {
+		name, short, scoped := strings.Cut(alias, "/")
+		if scoped && !c.knownProvider(name) {
+			return fmt.Errorf("config: model-aliases: %s: unknown provider %q (valid: %s)", alias, name, strings.Join(c.providerNames(), ", "))
+		}
+		if (scoped && short == "") || alias == "" {
+			return fmt.Errorf("config: model-aliases: empty alias %q", alias)
+		}
+		if id == "" {
+			return fmt.Errorf("config: model-aliases: %s has no model", alias)
+		}
+	}
+	for model, larger := range c.LargerModels {
+		if model == "" || larger == "" {
+			return fmt.Errorf("config: larger-models: %q: %q needs a model on both sides", model, larger)
+		}
+	}
+	for name, base := range c.BaseURLs {
+		if err := ai.CheckBaseURL(name, base); err != nil {
+			return fmt.Errorf("config: base-urls: %w", err)
+		}
+	}
+	if _, err := findStyle(c.CommitFormat); err != nil {
+		return fmt.Errorf("config: commit-format: %w", err)
+	}
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("config: max-tokens must not be negative")
+	}
+	if c.SubjectMax < 1 {
+		return fmt.Errorf("config: subject-max must be at least 1")
+	}
+	if c.ConfirmTokens < 0 {
+		return fmt.Errorf("config: confirm-tokens must not be negative")
+	}
+	for model, rate := range c.Rates {
+		if rate < 0 {
+			return fmt.Errorf("config: rates: %s must not be negative", model)
+		}
+	}
+	if _, err := regexp.Compile(c.IssuePattern); err != nil {
+		return fmt.Errorf("config: invalid issue-pattern: %w", err)
+	}
+	return nil
+}
+
+// providerNames returns the names of the built-in providers and then the
+// config's command providers, not including the auto provider.
+func (c *Config) providerNames() []string {
+	names := ai.Providers()
+	var commands []string
+	for name := range c.CommandProviders {
+		if !slices.Contains(names, name) {
+			commands = append(commands, name)
+		}
+	}
+	sort.Strings(commands)
+	return append(names, commands...)
+}
+
+// knownProvider reports whether name is a provider, built in or one of
+// the config's command providers, other than the auto provider.
+func (c *Config) knownProvider(name string) bool {
+	return slices.Contains(c.providerNames(), name)
+}
+
+// aiProvider returns p as an ai.CommandProvider.
+func (p CommandProvider) aiProvider() ai.CommandProvider {
+	return ai.CommandProvider{Command: p.Command, Model: p.Model, Local: p.Local}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyDataGlobalOnly(t *testing.T) {
+	for _, tt := range []struct {
+		key, data string
+	}{
+		{"command-providers", "command-providers:\n  mine:\n    command: ./run.sh\n"},
+		{"base-urls", "base-urls:\n  openai: https://gateway.example.com/v1\n"},
+		{"ca-cert", "ca-cert: certs/ca.pem\n"},
+		{"insecure-skip-verify", "insecure-skip-verify: true\n"},
+		{"audit-log", "audit-log: ../../.bashrc\n"},
+		{"audit-log", "audit-log: /tmp/audit.jsonl\n"},
+	} {
+		var c Config
+		err := c.applyData("/repo/.arc-ai.yaml", []byte(tt.data), false)
+		if err == nil || !strings.Contains(err.Error(), tt.key+" can only be set in the global config file") {
+			t.Errorf("%s in a repo config: err = %v, want it rejected", tt.key, err)
+		}
+
+		c = Config{}
+		if err := c.applyData("/home/me/.config/arc-ai/config.yaml", []byte(tt.data), true); err != nil {
+			t.Errorf("%s in the global config: %v", tt.key, err)
+		}
+	}
+}
+
+func TestApplyDataKeepsGlobalOnly(t *testing.T) {
+	// A repo config that sets other keys leaves the global values alone
+	var c Config
+	global := "audit-log: logs/audit.jsonl\nca-cert: /etc/ssl/corp.pem\n"
+	if err := c.applyData("/home/me/.config/arc-ai/config.yaml", []byte(global), true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.applyData("/repo/.arc-ai.yaml", []byte("audit-full: true\n"), false); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/home/me/.config/arc-ai/logs/audit.jsonl"; c.AuditLog != want {
+		t.Errorf("AuditLog = %q, want %q", c.AuditLog, want)
+	}
+	if c.CACert != "/etc/ssl/corp.pem" {
+		t.Errorf("CACert = %q", c.CACert)
+	}
+	if !c.AuditFull {
+		t.Error("AuditFull is not set")
+	}
+}
@@ -0,0 +1,509 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/internal/fileutil"
+	"gopkg.in/yaml.v3"
+)
+
+// configKey is a key of the config file: the YAML name of a Config field.
+type configKey struct {
+	name  string
+	field int
+	typ   reflect.Type
+}
+
+// configEntry is a value in the config, under a key such as max-tokens or,
+// for map entries, models.openai.
+type configEntry struct {
+	key, value string
+}
+
+// configFileOptions holds the flags that pick a config file.
+type configFileOptions struct {
+	global, local bool
+}
+
+func newConfigCmd() *cobra.Command {
+	var opts configFileOptions
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Get, set, and list config values",
+		Long: `Read and change config file values without editing the YAML by hand.
+
+Keys are those of the config file, such as provider or max-tokens. Entries
+of maps take the entry's name after a dot, as in models.openai or
+base-urls.openai, and lists such as providers are set from comma-separated
+values.
+
+get and list show the effective configuration, with the built-in defaults,
+ARC_AI_* variables, and both config files applied, or with --global or
+--local only what that file sets. set and unset change the repo-local
+.arc-ai.yaml, creating it at the root of the repository if need be, or
+with --global the global config file.
+
+Changes are checked as the commands that use the config would check them,
+such as that a provider is known, and nothing is written if they fail.
+Comments and the order of keys are kept. command-providers can only be
+edited by hand.`,
+	}
+
+	cmd.PersistentFlags().BoolVar(&opts.global, "global", false, "Use the global config file (~/.config/arc-ai/config.yaml)")
+	cmd.PersistentFlags().BoolVar(&opts.local, "local", false, "Use the repo-local config file ("+localConfigName+")")
+	cmd.MarkFlagsMutuallyExclusive("global", "local")
+
+	cmd.AddCommand(newConfigGetCmd(&opts))
+	cmd.AddCommand(newConfigSetCmd(&opts))
+	cmd.AddCommand(newConfigUnsetCmd(&opts))
+	cmd.AddCommand(newConfigListCmd(&opts))
+	return cmd
+}
+
+func newConfigGetCmd(opts *configFileOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a config value",
+		Long: `Print the value of a config key. A map, such as models, is printed as
+one name=value line per entry. Exits 1 if the key is not set.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeConfigKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := args[0]
+			if _, _, err := findConfigKey(key); err != nil {
+				return err
+			}
+			cfg, err := opts.read(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			var entries []configEntry
+			for _, e := range flattenConfig(cfg) {
+				if e.key == key {
+					fmt.Println(e.value)
+					return nil
+				}
+				if rest, ok := strings.CutPrefix(e.key, key+"."); ok {
+					entries = append(entries, configEntry{rest, e.value})
+				}
+			}
+			if len(entries) == 0 {
+				return fmt.Errorf("%s is not set", key)
+			}
+			for _, e := range entries {
+				fmt.Printf("%s=%s\n", e.key, e.value)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newConfigSetCmd(opts *configFileOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Set a config value",
+		Long: `Set a config key in the repo-local config file, or with --global the
+global one. The value must suit the key: a number for max-tokens, true or
+false for local-only, a known provider for provider.`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeConfigKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, entry, err := findConfigKey(args[0])
+			if err != nil {
+				return err
+			}
+			typ := key.typ
+			if typ.Kind() == reflect.Map {
+				if entry == "" {
+					return fmt.Errorf("%s is a map; set one of its entries, as in %s.<name>", key.name, key.name)
+				}
+				typ = typ.Elem()
+			}
+			value, err := configValueNode(typ, args[1])
+			if err != nil {
+				return fmt.Errorf("%s: %w", args[0], err)
+			}
+
+			path, err := opts.target(cmd.Context())
+			if err != nil {
+				return err
+			}
+			doc, err := readConfigNode(path)
+			if err != nil {
+				return err
+			}
+			m := doc.Content[0]
+			if entry == "" {
+				setMappingValue(m, key.name, value)
+			} else {
+				entries := mappingValue(m, key.name)
+				if entries == nil || entries.Kind != yaml.MappingNode {
+					entries = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+					setMappingValue(m, key.name, entries)
+				}
+				setMappingValue(entries, entry, value)
+			}
+			return writeConfigNode(path, doc)
+		},
+	}
+	return cmd
+}
+
+func newConfigUnsetCmd(opts *configFileOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a config value",
+		Long: `Remove a config key, or one entry of a map, from the repo-local config
+file, or with --global the global one, so that the value from elsewhere
+applies. Exits 1 if the file does not set it.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeConfigKeys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, entry, err := findConfigKey(args[0])
+			if err != nil {
+				return err
+			}
+			path, err := opts.target(cmd.Context())
+			if err != nil {
+				return err
+			}
+			doc, err := readConfigNode(path)
+			if err != nil {
+				return err
+			}
+
+			m := doc.Content[0]
+			removed := false
+			if entry == "" {
+				removed = deleteMappingValue(m, key.name)
+			} else if entries := mappingValue(m, key.name); entries != nil && entries.Kind == yaml.MappingNode {
+				removed = deleteMappingValue(entries, entry)
+				if len(entries.Content) == 0 {
+					deleteMappingValue(m, key.name)
+				}
+			}
+			if !removed {
+				return fmt.Errorf("%s is not set in %s", args[0], path)
+			}
+			return writeConfigNode(path, doc)
+		},
+	}
+	return cmd
+}
+
+func newConfigListCmd(opts *configFileOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List config values",
+		Long: `List the config values that are set, one key=value line each, with map
+entries under dotted keys such as models.openai.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := opts.read(cmd.Context())
+			if err != nil {
+				return err
+			}
+			for _, e := range flattenConfig(cfg) {
+				fmt.Printf("%s=%s\n", e.key, e.value)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// read returns the config that get and list show: the effective one, or
+// with --global or --local only what that file sets, unvalidated.
+func (o *configFileOptions) read(ctx context.Context) (*Config, error) {
+	if !o.global && !o.local {
+		return LoadConfig()
+	}
+	path, err := o.target(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// target returns the config file to change: the global one with --global,
+// or else the local one, which is created at the root of the repository
+// if there is none.
+func (o *configFileOptions) target(ctx context.Context) (string, error) {
+	if o.global {
+		return globalConfigPath()
+	}
+	if path := findLocalConfig(); path != "" {
+		return path, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := requireRepo(ctx); err != nil {
+		return "", fmt.Errorf("%w; use --global for the global config file", err)
+	}
+	root, err := git(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, localConfigName), nil
+}
+
+// configKeys returns the keys of the config file in the order of Config's
+// fields.
+func configKeys() []configKey {
+	t := reflect.TypeOf(Config{})
+	keys := make([]configKey, t.NumField())
+	for i := range keys {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		keys[i] = configKey{name: name, field: i, typ: t.Field(i).Type}
+	}
+	return keys
+}
+
+// findConfigKey splits key into a key of the config file and, for a map,
+// the name of an entry in it, which may be empty.
+func findConfigKey(key string) (configKey, string, error) {
+	name, entry, dotted := strings.Cut(key, ".")
+	var names []string
+	for _, k := range configKeys() {
+		names = append(names, k.name)
+		if k.name != name {
+			continue
+		}
+		if dotted && (k.typ.Kind() != reflect.Map || entry == "") {
+			return configKey{}, "", fmt.Errorf("unknown config key %q: %s has no entries", key, name)
+		}
+		return k, entry, nil
+	}
+	return configKey{}, "", fmt.Errorf("unknown config key %q (valid: %s)", name, strings.Join(names, ", "))
+}
+
+// flattenConfig returns the values set in cfg in the order of its keys,
+// with map entries, sorted by name, and the fields of their values under
+// dotted keys.
+func flattenConfig(cfg *Config) []configEntry {
+	v := reflect.ValueOf(cfg).Elem()
+	var entries []configEntry
+	for _, k := range configKeys() {
+		entries = appendConfigEntries(entries, k.name, v.Field(k.field), false)
+	}
+	return entries
+}
+
+// appendConfigEntries appends the entries of v under key, leaving out a
+// zero value unless keepZero is set.
+func appendConfigEntries(entries []configEntry, key string, v reflect.Value, keepZero bool) []configEntry {
+	switch v.Kind() {
+	case reflect.Map:
+		names := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			names = append(names, k.String())
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			entries = appendConfigEntries(entries, key+"."+name, v.MapIndex(reflect.ValueOf(name)), true)
+		}
+		return entries
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			entries = appendConfigEntries(entries, key+"."+name, v.Field(i), false)
+		}
+		return entries
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return entries
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return append(entries, configEntry{key, strings.Join(items, ",")})
+	}
+	if v.IsZero() && !keepZero {
+		return entries
+	}
+	return append(entries, configEntry{key, fmt.Sprint(v.Interface())})
+}
+
+// configValueNode parses value as a config value of type t and returns it
+// as a YAML node.
+func configValueNode(t reflect.Type, value string) (*yaml.Node, error) {
+	switch t.Kind() {
+	case reflect.String:
+		// Quoted when needed, so that "true" or "8000" stays a string
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}, nil
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a whole number", value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.Itoa(n)}, nil
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatFloat(f, 'g', -1, 64)}, nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not true or false", value)
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatBool(b)}, nil
+	case reflect.Slice:
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			n, err := configValueNode(t.Elem(), item)
+			if err != nil {
+				return nil, err
+			}
+			seq.Content = append(seq.Content, n)
+		}
+		return seq, nil
+	}
+	return nil, fmt.Errorf("cannot be set with 'arc-ai config set'; edit the config file")
+}
+
+// readConfigNode parses the config file at path, keeping its comments and
+// layout. A missing or empty file gives an empty document. The document's
+// content is a single mapping.
+func readConfigNode(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc.Kind = yaml.DocumentNode
+	}
+	if len(doc.Content) == 0 {
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config %s is not a map of keys to values", path)
+	}
+	return &doc, nil
+}
+
+// writeConfigNode writes doc to the config file at path, once the config
+// with it loads and validates.
+func writeConfigNode(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if _, err := loadConfig(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("%w; %s not changed", err, path)
+	}
+	// The temporary file WriteAtomic renames is private
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := fileutil.WriteAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value of key in the mapping node m, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key in the mapping node m to value, keeping the
+// comments of a value it replaces, or adds it at the end.
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			old := m.Content[i+1]
+			value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// deleteMappingValue removes key from the mapping node m and reports
+// whether it was there.
+func deleteMappingValue(m *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// completeConfigKeys offers the config keys as the first argument, with a
+// dot after those of maps for their entries.
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var keys []string
+	directive := cobra.ShellCompDirectiveNoFileComp
+	for _, k := range configKeys() {
+		if k.typ.Kind() == reflect.Map {
+			keys = append(keys, k.name+".")
+			if strings.HasPrefix(k.name+".", toComplete) {
+				directive |= cobra.ShellCompDirectiveNoSpace
+			}
+			continue
+		}
+		keys = append(keys, k.name)
+	}
+	return keys, directive
+}
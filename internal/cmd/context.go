@@ -0,0 +1,256 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-ai/internal/ignore"
+)
+
+// contextIgnoreFile holds gitignore-style patterns for files to leave out
+// when a --context directory is expanded, on top of .gitignore.
+const contextIgnoreFile = ".arc-ai-ignore"
+
+// maxUntrackedSize is the largest untracked file, in bytes, whose contents
+// commit --include-untracked sends; larger files are only named.
+const maxUntrackedSize = 256 << 10
+
+// untrackedShare divides the token budget of commit --include-untracked:
+// untracked files get a quarter of it, and the diff the rest.
+const untrackedShare = 4
+
+// expandContextPaths resolves --context arguments to files. A directory
+// expands to the files beneath it that git does not ignore, or to all of
+// its files outside a git repository, less those matched by
+// .arc-ai-ignore files. Files named directly are always included.
+func expandContextPaths(ctx context.Context, paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("context: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		dirFiles, err := listDir(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+// listDir lists the regular files under dir, honoring .gitignore when dir
+// is inside a git repository, and .arc-ai-ignore files.
+func listDir(ctx context.Context, dir string) ([]string, error) {
+	out, err := git(ctx, "-C", dir, "ls-files", "--cached", "--others", "--exclude-standard")
+	if err == nil {
+		var names []string
+		for _, name := range strings.Split(out, "\n") {
+			// Tracked files may have been deleted from the working tree
+			if info, err := os.Stat(filepath.Join(dir, name)); name != "" && err == nil && info.Mode().IsRegular() {
+				names = append(names, name)
+			}
+		}
+		// .arc-ai-ignore files apply from the top of the working tree down
+		prefix, err := git(ctx, "-C", dir, "rev-parse", "--show-prefix")
+		if err != nil {
+			return nil, err
+		}
+		return filterIgnored(dir, prefix, names)
+	}
+
+	var names []string
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			name, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(name))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("context: %w", err)
+	}
+	return filterIgnored(dir, "", names)
+}
+
+// filterIgnored returns the paths of the files under dir, named by their
+// slash-separated paths relative to it, that no .arc-ai-ignore file
+// matches. prefix is dir's path from the root of the tree whose ignore
+// files apply, such as the top of the git working tree; the files in dir's
+// parents up to that root apply too.
+func filterIgnored(dir, prefix string, names []string) ([]string, error) {
+	prefix = strings.Trim(prefix, "/")
+	root := dir
+	if prefix != "" {
+		root = filepath.Join(dir, strings.Repeat("../", strings.Count(prefix, "/")+1))
+	}
+
+	// Load the ignore files of every directory holding a file, shallowest
+	// first so that deeper ones take precedence
+	dirs := map[string]bool{"": true}
+	for _, name := range names {
+		for d := path.Dir(path.Join(prefix, name)); d != "."; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	sorted := make([]string, 0, len(dirs))
+	for d := range dirs {
+		sorted = append(sorted, d)
+	}
+	depth := func(d string) int {
+		if d == "" {
+			return 0
+		}
+		return strings.Count(d, "/") + 1
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if depth(sorted[i]) != depth(sorted[j]) {
+			return depth(sorted[i]) < depth(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	var m ignore.Matcher
+	for _, d := range sorted {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(d), contextIgnoreFile))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("context: %w", err)
+		}
+		m.Add(d, string(data))
+	}
+
+	var files []string
+	for _, name := range names {
+		if m.Match(path.Join(prefix, name), false) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, filepath.FromSlash(name)))
+	}
+	return files, nil
+}
+
+// attachContext reads files and formats them as fenced blocks labeled with
+// their paths. Files are truncated so the blocks fit within maxTokens: the
+// budget is shared evenly, with whatever small files leave unused passed on
+// to larger ones. Binary files are skipped.
+func attachContext(files []string, maxTokens int) (string, error) {
+	contents := make([]string, len(files))
+	for i, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("context: %w", err)
+		}
+		if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			continue
+		}
+		contents[i] = string(data)
+	}
+
+	// Smallest first, so each file's unused share goes to the larger ones
+	order := make([]int, len(files))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(contents[order[a]]) < len(contents[order[b]])
+	})
+
+	blocks := make([]string, len(files))
+	remaining := maxTokens
+	for k, i := range order {
+		if contents[i] == "" {
+			continue
+		}
+		content := contents[i]
+		if maxTokens > 0 {
+			header := estimateTokens(contextBlock(files[i], ""))
+			// A limit of 0 would mean no limit to truncateText
+			content = truncateText(content, max(remaining/(len(order)-k)-header, 1))
+		}
+		blocks[i] = contextBlock(files[i], content)
+		remaining -= estimateTokens(blocks[i])
+	}
+
+	return strings.Join(blocks, ""), nil
+}
+
+// contextBlock fences content under its path. The fence is longer than any
+// run of backticks in content so the block cannot end early.
+func contextBlock(path, content string) string {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	return fmt.Sprintf("File: %s\n%s\n%s\n%s\n\n", path, fence, strings.TrimRight(content, "\n"), fence)
+}
+
+// untrackedContext returns the untracked files that git does not ignore,
+// limited and filtered like gitDiff, as context blocks within maxTokens.
+// Binary files are left out, and files over maxUntrackedSize are named
+// without their contents.
+func untrackedContext(ctx context.Context, paths, exclude []string, maxTokens int) (string, error) {
+	specs := pathspecs(paths, exclude)
+	if specs == nil {
+		// Unlike git diff, ls-files lists only the current directory
+		specs = []string{"--", ":/"}
+	}
+	args := append([]string{"ls-files", "--others", "--exclude-standard", "-z"}, specs...)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	var files, large []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+		info, err := os.Lstat(name)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if info.Size() > maxUntrackedSize {
+			large = append(large, name)
+			continue
+		}
+		files = append(files, name)
+	}
+
+	text, err := attachContext(files, maxTokens)
+	if err != nil {
+		return "", err
+	}
+	if len(large) > 0 {
+		text += "Too large to include: " + strings.Join(large, ", ") + "\n"
+	}
+	return strings.TrimSpace(text), nil
+}
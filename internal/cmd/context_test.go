@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeFiles creates the files in dir, by slash-separated path, with their
+// contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, text := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFilterIgnoredLayers(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		contextIgnoreFile:                "*.golden\nsecret.txt\n",
+		"pkg/" + contextIgnoreFile:       "!keep.golden\n",
+		"pkg/deep/" + contextIgnoreFile:  "keep.golden\n",
+		"pkg/other/" + contextIgnoreFile: "/local.go\n",
+		"a.golden":                       "",
+		"main.go":                        "",
+		"pkg/keep.golden":                "",
+		"pkg/drop.golden":                "",
+		"pkg/secret.txt":                 "",
+		"pkg/deep/keep.golden":           "",
+		"pkg/other/local.go":             "",
+		"pkg/other/sub/local.go":         "",
+	})
+	names := []string{
+		"a.golden", "main.go",
+		"pkg/keep.golden", "pkg/drop.golden", "pkg/secret.txt",
+		"pkg/deep/keep.golden",
+		"pkg/other/local.go", "pkg/other/sub/local.go",
+	}
+
+	files, err := filterIgnored(root, "", names)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"main.go", "pkg/keep.golden", "pkg/other/sub/local.go"}
+	var got []string
+	for _, f := range files {
+		rel, _ := filepath.Rel(root, f)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("kept %q, want %q", got, want)
+	}
+}
+
+func TestFilterIgnoredPrefix(t *testing.T) {
+	// The ignore files of the directories above dir, up to the root of the
+	// tree, apply to the files in it
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		contextIgnoreFile:          "*.golden\n",
+		"pkg/" + contextIgnoreFile: "!keep.golden\n",
+		"pkg/sub/a.golden":         "",
+		"pkg/sub/keep.golden":      "",
+		"pkg/sub/a.go":             "",
+	})
+	dir := filepath.Join(root, "pkg", "sub")
+
+	files, err := filterIgnored(dir, "pkg/sub", []string{"a.golden", "keep.golden", "a.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "keep.golden"), filepath.Join(dir, "a.go")}
+	if !slices.Equal(files, want) {
+		t.Errorf("kept %q, want %q", files, want)
+	}
+}
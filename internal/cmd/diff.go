@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// defaultMaxTokens is the default token budget for diffs sent to the AI.
+// It leaves ample room for the prompt and response within the context
+// window of current hosted models.
+const defaultMaxTokens = 8000
+
+// estimateTokens approximates the number of tokens in s.
+func estimateTokens(s string) int {
+	return ai.EstimateTokens(s)
+}
+
+// truncateText shortens s to fit within maxTokens, cutting on a line
+// boundary and noting how many lines were dropped.
+func truncateText(s string, maxTokens int) string {
+	if maxTokens <= 0 || estimateTokens(s) <= maxTokens {
+		return s
+	}
+
+	lines := strings.SplitAfter(s, "\n")
+	var b strings.Builder
+	used := 0
+	for i, line := range lines {
+		if used+estimateTokens(line) > maxTokens {
+			return b.String() + fmt.Sprintf("\n... (truncated: %s omitted)\n", plural(len(lines)-i, "line"))
+		}
+		b.WriteString(line)
+		used += estimateTokens(line)
+	}
+	return b.String()
+}
+
+// diffFile is one file's section of a unified diff.
+type diffFile struct {
+	path   string
+	header string   // "diff --git" line through the line before the first hunk
+	hunks  []string // each hunk starts with an "@@" line
+}
+
+func (f diffFile) String() string {
+	return f.header + strings.Join(f.hunks, "")
+}
+
+// parseDiff splits a unified diff into per-file sections and hunks.
+// Anything before the first "diff --git" line is kept as a file with an
+// empty path so that plain (non-git) diffs survive a round trip. The
+// "Submodule" lines of --submodule=log and =diff each start a section of
+// their own, for the submodule's path.
+func parseDiff(diff string) []diffFile {
+	var files []diffFile
+	var cur *diffFile
+
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, diffFile{path: diffPath(line)})
+			cur = &files[len(files)-1]
+			cur.header = line
+		case submoduleLine.MatchString(line):
+			files = append(files, diffFile{path: submoduleLine.FindStringSubmatch(line)[1]})
+			cur = &files[len(files)-1]
+			cur.header = line
+		case strings.HasPrefix(line, "@@"):
+			if cur == nil {
+				files = append(files, diffFile{})
+				cur = &files[len(files)-1]
+			}
+			cur.hunks = append(cur.hunks, line)
+		default:
+			if cur == nil {
+				files = append(files, diffFile{})
+				cur = &files[len(files)-1]
+			}
+			if len(cur.hunks) == 0 {
+				cur.header += line
+			} else {
+				cur.hunks[len(cur.hunks)-1] += line
+			}
+		}
+	}
+
+	return files
+}
+
+// submoduleLine matches the line git diff --submodule starts a changed
+// submodule with, such as "Submodule lib 1a2b3c4..5d6e7f8:".
+var submoduleLine = regexp.MustCompile(`^Submodule (.+?) (?:[0-9a-f]+\.\.\.?[0-9a-f]+|contains )`)
+
+// diffPath extracts the new-side path from a "diff --git a/x b/x" line.
+func diffPath(line string) string {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "diff --git "))
+	if i := strings.LastIndex(line, " b/"); i >= 0 {
+		return line[i+3:]
+	}
+	return line
+}
+
+// hunkHeader matches the "@@" line that starts a hunk, capturing the first
+// line number on the new side.
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// diffLine is an added or unchanged line of a file in a diff, which an
+// inline review comment can be attached to.
+type diffLine struct {
+	// position counts the lines below the file's first "@@" line, through
+	// later hunks, as GitHub review comments expect.
+	position int
+	// text is the line without its diff prefix.
+	text string
+}
+
+// walkHunks calls fn with each line of the hunks in f: its GitHub position,
+// and its line number in the new file, or 0 for a removed line or an "@@"
+// or "\ No newline" line.
+func (f diffFile) walkHunks(fn func(position, line int, text string)) {
+	position, next := -1, 0
+	for _, h := range f.hunks {
+		for _, text := range strings.Split(strings.TrimSuffix(h, "\n"), "\n") {
+			position++ // from 0 for the first "@@" line
+			line := 0
+			switch {
+			case strings.HasPrefix(text, "@@"):
+				if m := hunkHeader.FindStringSubmatch(text); m != nil {
+					fmt.Sscan(m[1], &next)
+				}
+			case strings.HasPrefix(text, "+"), strings.HasPrefix(text, " "):
+				line = next
+				next++
+			}
+			fn(position, line, text)
+		}
+	}
+}
+
+// diffLines maps each file in diff to its added and unchanged lines, by
+// line number in the new file.
+func diffLines(diff string) map[string]map[int]diffLine {
+	files := map[string]map[int]diffLine{}
+	for _, f := range parseDiff(diff) {
+		if f.path == "" || len(f.hunks) == 0 {
+			continue
+		}
+		lines := map[int]diffLine{}
+		f.walkHunks(func(position, line int, text string) {
+			if line > 0 {
+				lines[line] = diffLine{position: position, text: text[1:]}
+			}
+		})
+		files[f.path] = lines
+	}
+	return files
+}
+
+// numberDiff prefixes each line of the hunks in diff with its line number
+// in the new file, left blank for removed lines, so the AI can cite lines
+// without counting.
+func numberDiff(diff string) string {
+	var b strings.Builder
+	for _, f := range parseDiff(diff) {
+		b.WriteString(f.header)
+		f.walkHunks(func(_, line int, text string) {
+			switch {
+			case line > 0:
+				fmt.Fprintf(&b, "%6d %s\n", line, text)
+			case strings.HasPrefix(text, "-"), strings.HasPrefix(text, "\\"):
+				fmt.Fprintf(&b, "%6s %s\n", "", text)
+			default:
+				b.WriteString(text + "\n")
+			}
+		})
+	}
+	return b.String()
+}
+
+// changedLines counts the added and removed lines in the file's hunks.
+func (f diffFile) changedLines() int {
+	n := 0
+	for _, h := range f.hunks {
+		for _, line := range strings.SplitAfter(h, "\n") {
+			if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// rankDiffFiles orders files by the number of changed lines, largest first,
+// breaking ties by path so the result is deterministic.
+func rankDiffFiles(files []diffFile) []diffFile {
+	ranked := make([]diffFile, len(files))
+	copy(ranked, files)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ci, cj := ranked[i].changedLines(), ranked[j].changedLines()
+		if ci != cj {
+			return ci > cj
+		}
+		return ranked[i].path < ranked[j].path
+	})
+	return ranked
+}
+
+// truncateDiff shortens diff to fit within maxTokens. Files are ranked with
+// rankDiffFiles and included whole, most substantial first, while they fit.
+// Any remaining budget is spent on leading hunks of the files that did not
+// fit; hunks are never split, unless nothing fits at all: then the top
+// file's first hunk is cut by lines, so that the AI still sees the
+// largest change. When anything is dropped, a note saying how much is
+// appended.
+func truncateDiff(diff string, maxTokens int) string {
+	if maxTokens <= 0 || estimateTokens(diff) <= maxTokens {
+		return diff
+	}
+
+	ranked := rankDiffFiles(parseDiff(diff))
+	kept := make([]bool, len(ranked))
+	hunks := make([]int, len(ranked)) // hunks included per ranked file
+	used := 0
+
+	// First pass: whole files, most substantial first.
+	for i, f := range ranked {
+		if cost := estimateTokens(f.String()); used+cost <= maxTokens {
+			kept[i] = true
+			hunks[i] = len(f.hunks)
+			used += cost
+		}
+	}
+
+	// Second pass: leading hunks of the files that did not fit whole.
+	for i, f := range ranked {
+		if kept[i] || len(f.hunks) == 0 {
+			continue
+		}
+		if used+estimateTokens(f.header)+estimateTokens(f.hunks[0]) > maxTokens {
+			continue
+		}
+		kept[i] = true
+		used += estimateTokens(f.header)
+		for _, h := range f.hunks {
+			if used+estimateTokens(h) > maxTokens {
+				break
+			}
+			hunks[i]++
+			used += estimateTokens(h)
+		}
+	}
+
+	// A single hunk over the budget is the common case for a big change
+	var partial string
+	if used == 0 && len(ranked) > 0 {
+		f := ranked[0]
+		first := f.header
+		if len(f.hunks) > 0 {
+			first += f.hunks[0]
+			hunks[0] = 1
+		}
+		partial = truncateText(first, maxTokens)
+		kept[0] = true
+	}
+
+	var b strings.Builder
+	droppedFiles, droppedHunks := 0, 0
+	for i, f := range ranked {
+		droppedHunks += len(f.hunks) - hunks[i]
+		if !kept[i] {
+			droppedFiles++
+			continue
+		}
+		if partial != "" && i == 0 {
+			b.WriteString(partial)
+			continue
+		}
+		b.WriteString(f.header)
+		for _, h := range f.hunks[:hunks[i]] {
+			b.WriteString(h)
+		}
+	}
+
+	return b.String() + truncationNote(droppedFiles, droppedHunks)
+}
+
+func truncationNote(files, hunks int) string {
+	var parts []string
+	if files > 0 {
+		parts = append(parts, plural(files, "file"))
+	}
+	if hunks > 0 {
+		parts = append(parts, plural(hunks, "hunk"))
+	}
+	return fmt.Sprintf("\n... (truncated: %s omitted)\n", strings.Join(parts, ", "))
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fileDiff returns a git diff of a new file at path with a hunk of
+// that many added lines for each of hunks.
+func fileDiff(path string, hunks ...int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\nnew file mode 100644\n--- /dev/null\n+++ b/%s\n", path, path, path)
+	start := 1
+	for _, n := range hunks {
+		fmt.Fprintf(&b, "@@ -0,0 +%d,%d @@\n", start, n)
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&b, "+%s line %d\n", path, start+i)
+		}
+		start += n + 10
+	}
+	return b.String()
+}
+
+func paths(files []diffFile) []string {
+	var p []string
+	for _, f := range files {
+		p = append(p, f.path)
+	}
+	return p
+}
+
+func TestRankDiffFiles(t *testing.T) {
+	diff := fileDiff("small.go", 2) + fileDiff("big.go", 30) + fileDiff("b.go", 5, 5) + fileDiff("a.go", 10)
+	got := strings.Join(paths(rankDiffFiles(parseDiff(diff))), " ")
+	// a.go and b.go tie on 10 changed lines; the path breaks it
+	if want := "big.go a.go b.go small.go"; got != want {
+		t.Errorf("ranked %s, want %s", got, want)
+	}
+}
+
+func TestTruncateDiffWithinBudget(t *testing.T) {
+	diff := fileDiff("a.go", 3) + fileDiff("b.go", 3)
+	if got := truncateDiff(diff, estimateTokens(diff)); got != diff {
+		t.Errorf("a diff within the budget was changed:\n%s", got)
+	}
+	if got := truncateDiff(diff, 0); got != diff {
+		t.Errorf("a budget of 0 changed the diff:\n%s", got)
+	}
+}
+
+func TestTruncateDiffBudget(t *testing.T) {
+	big := fileDiff("big.go", 40, 40)
+	small := fileDiff("small.go", 5)
+	tiny := fileDiff("tiny.go", 1)
+	diff := tiny + small + big
+
+	for _, tt := range []struct {
+		name      string
+		maxTokens int
+		kept      []string
+		dropped   []string
+		note      string
+	}{
+		{
+			// big.go fits whole and takes all the room
+			name:      "largest file first",
+			maxTokens: estimateTokens(big),
+			kept:      []string{"big.go line 1\n", "big.go line 90\n"},
+			dropped:   []string{"small.go", "tiny.go"},
+			note:      "(truncated: 2 files, 2 hunks omitted)",
+		},
+		{
+			// The small files fit whole, then big.go's first hunk but not its second
+			name:      "leading hunks of a file that does not fit",
+			maxTokens: estimateTokens(small+tiny) + estimateTokens(big)/2 + 10,
+			kept:      []string{"small.go line 5\n", "tiny.go line 1\n", "big.go line 40\n"},
+			dropped:   []string{"big.go line 51\n"},
+			note:      "(truncated: 1 hunk omitted)",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateDiff(diff, tt.maxTokens)
+			for _, s := range tt.kept {
+				if !strings.Contains(got, s) {
+					t.Errorf("%q was dropped", s)
+				}
+			}
+			for _, s := range tt.dropped {
+				if strings.Contains(got, s) {
+					t.Errorf("%q was kept", s)
+				}
+			}
+			if !strings.HasSuffix(got, tt.note+"\n") {
+				t.Errorf("got %q at the end, want the note %q", got[max(0, len(got)-60):], tt.note)
+			}
+		})
+	}
+}
+
+func TestTruncateDiffHugeHunks(t *testing.T) {
+	// No file, nor any file's first hunk, fits in the budget
+	diff := fileDiff("a.go", 200) + fileDiff("b.go", 300)
+	got := truncateDiff(diff, 100)
+
+	if estimateTokens(got) > 100+20 {
+		t.Errorf("got ~%d tokens, want about 100 at most", estimateTokens(got))
+	}
+	for _, s := range []string{"diff --git a/b.go b/b.go\n", "+b.go line 1\n"} {
+		if !strings.Contains(got, s) {
+			t.Errorf("the largest file's start, %q, was dropped:\n%s", s, got)
+		}
+	}
+	if strings.Contains(got, "a.go") {
+		t.Errorf("a.go was kept:\n%s", got)
+	}
+	if !strings.Contains(got, "lines omitted)") || !strings.HasSuffix(got, "(truncated: 1 file, 1 hunk omitted)\n") {
+		t.Errorf("missing truncation notes:\n%s", got)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// undocumented is an exported declaration without a doc comment.
+type undocumented struct {
+	// name is the symbol name, or Type.Method for methods.
+	name string
+	// line is the 1-based line the declaration starts on, where its
+	// comment goes.
+	line int
+	// decl is the source of the declaration, shown to the AI.
+	decl string
+}
+
+func newDocstringCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var write bool
+	var maxTokens int
+
+	cmd := &cobra.Command{
+		Use:   "docstring <file>",
+		Short: "Write doc comments for undocumented exported Go symbols",
+		Long: `Write GoDoc comments for the exported declarations in a Go file that
+have none.
+
+Only exported functions, methods on exported types, types, constants, and
+variables without a doc comment are touched; the rest of the file is left
+exactly as it is. A constant or variable group with a comment counts as
+documented.
+
+The comments are printed as a patch, or added to the file with --write.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			path := args[0]
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", path, err)
+			}
+			src := string(data)
+
+			symbols, err := findUndocumented(path, src)
+			if err != nil {
+				return err
+			}
+			if len(symbols) == 0 {
+				fmt.Println("All exported declarations are documented.")
+				return nil
+			}
+
+			names := make([]string, len(symbols))
+			decls := make([]string, len(symbols))
+			for i, s := range symbols {
+				names[i] = s.name
+				decls[i] = s.decl
+			}
+
+			prompt := fmt.Sprintf(`Write idiomatic GoDoc comments for these exported declarations from %s:
+%s
+
+Each comment is a complete sentence, or a few, that starts with the
+symbol's name (the method name for Type.Method) and says what it does or
+represents, not how. Keep them brief.
+Respond with ONLY a JSON object mapping each name above to its comment
+text, without the // markers, using \n between lines kept under 80
+characters. No explanations.
+
+Declarations:
+%s
+
+The whole file, for context:
+%s`, path, strings.Join(names, ", "), strings.Join(decls, "\n\n"), contextBlock(path, truncateText(src, maxTokens)))
+
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+				return err
+			}
+
+			response, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			comments := map[string]string{}
+			if err := decodeResponseJSON(response, &comments); err != nil {
+				return err
+			}
+
+			updated := insertDocComments(src, symbols, comments)
+			if updated == src {
+				return fmt.Errorf("AI response had no comments for %s", strings.Join(names, ", "))
+			}
+
+			if write {
+				info, err := os.Stat(path)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+					return fmt.Errorf("write %s: %w", path, err)
+				}
+				fmt.Printf("Documented %s in %s\n", plural(len(symbols), "declaration"), path)
+				return nil
+			}
+
+			patch, err := filePatch(ctx, path, src, updated)
+			if err != nil {
+				return err
+			}
+			fmt.Print(patch)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().BoolVar(&write, "write", false, "Add the comments to the file instead of printing a patch")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the file sent to the AI")
+
+	return cmd
+}
+
+// findUndocumented parses the Go source src and returns its exported
+// declarations that lack a doc comment, in source order.
+func findUndocumented(path, src string) ([]undocumented, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	add := func(symbols []undocumented, name string, node ast.Node) []undocumented {
+		start, end := fset.Position(node.Pos()).Offset, fset.Position(node.End()).Offset
+		return append(symbols, undocumented{name: name, line: fset.Position(node.Pos()).Line, decl: src[start:end]})
+	}
+
+	var symbols []undocumented
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil || !d.Name.IsExported() {
+				continue
+			}
+			name := funcName(d)
+			if recv, _, ok := strings.Cut(name, "."); ok && !ast.IsExported(recv) {
+				continue
+			}
+			// The signature is enough to describe a function
+			sig := *d
+			sig.Body = nil
+			symbols = add(symbols, name, &sig)
+
+		case *ast.GenDecl:
+			if d.Doc != nil || d.Tok == token.IMPORT {
+				continue
+			}
+			for _, spec := range d.Specs {
+				name, doc := specName(spec)
+				if doc != nil || !ast.IsExported(name) {
+					continue
+				}
+				// A declaration of one spec without parentheses is
+				// commented above the keyword
+				if !d.Lparen.IsValid() {
+					symbols = add(symbols, name, d)
+				} else {
+					symbols = add(symbols, name, spec)
+				}
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// specName returns the name declared by a type, const, or var spec and its
+// doc comment. Specs declaring several names use the first.
+func specName(spec ast.Spec) (string, *ast.CommentGroup) {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Name.Name, s.Doc
+	case *ast.ValueSpec:
+		return s.Names[0].Name, s.Doc
+	}
+	return "", nil
+}
+
+// insertDocComments adds each symbol's comment above its declaration,
+// indented to match it. Symbols without a comment are skipped.
+func insertDocComments(src string, symbols []undocumented, comments map[string]string) string {
+	lines := strings.SplitAfter(src, "\n")
+
+	// Insert from the bottom so earlier line numbers stay valid
+	sorted := append([]undocumented(nil), symbols...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].line > sorted[j].line })
+
+	for _, s := range sorted {
+		text := strings.TrimSpace(comments[s.name])
+		if text == "" {
+			continue
+		}
+		target := lines[s.line-1]
+		indent := target[:len(target)-len(strings.TrimLeft(target, " \t"))]
+
+		var comment []string
+		for _, l := range strings.Split(text, "\n") {
+			l = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "//"))
+			if l == "" {
+				comment = append(comment, indent+"//\n")
+			} else {
+				comment = append(comment, indent+"// "+l+"\n")
+			}
+		}
+		lines = append(lines[:s.line-1], append(comment, lines[s.line-1:]...)...)
+	}
+	return strings.Join(lines, "")
+}
+
+// filePatch returns a unified diff from before to after for the file at
+// path, as git would show it.
+func filePatch(ctx context.Context, path, before, after string) (string, error) {
+	dir, err := os.MkdirTemp("", "arc-ai-patch-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) || strings.HasPrefix(name, "../") {
+		name = filepath.Base(path)
+	}
+	for prefix, content := range map[string]string{"a": before, "b": after} {
+		p := filepath.Join(dir, prefix, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "diff", "--no-index", "--no-color", "--no-prefix",
+		"a/"+name, "b/"+name).Output()
+	// Exit status 1 means the files differ
+	var exitErr *exec.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 1) {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(out), nil
+}
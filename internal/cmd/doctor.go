@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/ai"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// probeTimeout bounds each provider check run by doctor.
+const probeTimeout = 5 * time.Second
+
+// providerStatus is the result of checking one provider.
+type providerStatus struct {
+	Provider string `json:"provider"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail"`
+}
+
+func newDoctorCmd() *cobra.Command {
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check which AI providers are usable",
+		Long: `Check each AI provider and report whether it can be used.
+
+CLI providers must be on PATH and runnable, API providers need their key
+set, and Ollama must be reachable. The command-providers in the config
+file must be valid and their commands on PATH; they are not run. Exits
+with code 3 if no provider is usable.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			// An invalid config would leave its command providers out
+			if _, err := LoadConfig(); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			statuses := checkProviders(ctx)
+
+			if out.Is(output.OutputJSON) {
+				if err := output.JSON(statuses); err != nil {
+					return err
+				}
+			} else {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "PROVIDER\tSTATUS\tDETAIL")
+				for _, s := range statuses {
+					status := "ok"
+					if !s.OK {
+						status = "unavailable"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", s.Provider, status, s.Detail)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+
+			for _, s := range statuses {
+				if s.OK {
+					return nil
+				}
+			}
+			return &Error{Kind: KindNoProvider, Msg: "no usable AI provider"}
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+// checkProviders runs the availability check and probe for every provider.
+func checkProviders(ctx context.Context) []providerStatus {
+	names := ai.Providers()
+	statuses := make([]providerStatus, 0, len(names))
+	for _, name := range names {
+		s := providerStatus{Provider: name}
+
+		if err := ai.Available(name); err != nil {
+			s.Detail = err.Error()
+		} else {
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			detail, err := ai.Probe(probeCtx, name)
+			cancel()
+			if err != nil {
+				s.Detail = err.Error()
+			} else {
+				s.OK = true
+				s.Detail = detail
+			}
+		}
+
+		statuses = append(statuses, s)
+	}
+	return statuses
+}
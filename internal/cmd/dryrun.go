@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// promptPreview is what would be sent for a request, shown in its place by
+// ask --dry-run and commit --print-prompt.
+type promptPreview struct {
+	Provider string `json:"provider" yaml:"provider"`
+	// Model is "" if the provider chooses for itself.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+	// Unavailable says why no provider could be chosen, if none could.
+	Unavailable string `json:"unavailable,omitempty" yaml:"unavailable,omitempty"`
+	System      string `json:"system,omitempty" yaml:"system,omitempty"`
+	Prompt      string `json:"prompt" yaml:"prompt"`
+}
+
+// previewRequest returns what req would send. No provider being available
+// is not an error here, since the prompt can be shown all the same.
+func previewRequest(req aiRequest) promptPreview {
+	sent, err := ai.Prepare(req.Request)
+	if err != nil {
+		provider := req.Provider
+		if provider == "" {
+			provider = providerAuto
+		}
+		return promptPreview{
+			Provider:    provider,
+			Model:       req.Model,
+			Unavailable: err.Error(),
+			System:      req.System,
+			Prompt:      req.Prompt,
+		}
+	}
+	return promptPreview{Provider: sent.Provider, Model: sent.Model, System: sent.System, Prompt: sent.Prompt}
+}
+
+// print writes p as text: the provider and model, then the system prompt,
+// if any, and the prompt, each under a "==> name <==" header.
+func (p promptPreview) print(w io.Writer) {
+	fmt.Fprintf(w, "provider: %s\n", p.Provider)
+	if p.Unavailable != "" {
+		fmt.Fprintf(w, "unavailable: %s\n", p.Unavailable)
+	}
+	model := p.Model
+	if model == "" {
+		model = "(provider default)"
+	}
+	fmt.Fprintf(w, "model: %s\n", model)
+	if p.System != "" {
+		fmt.Fprintf(w, "\n==> system <==\n%s\n", p.System)
+	}
+	fmt.Fprintf(w, "\n==> prompt <==\n%s\n", p.Prompt)
+}
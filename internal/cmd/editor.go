@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const editorHint = `
+# Edit the commit message above. Lines starting with '#' are ignored,
+# and an empty message aborts the commit.
+`
+
+// editorCommand returns the user's editor command line: $EDITOR, or vi or
+// nano if available.
+func editorCommand() ([]string, error) {
+	if editor := strings.Fields(os.Getenv("EDITOR")); len(editor) > 0 {
+		return editor, nil
+	}
+	for _, name := range []string{"vi", "nano"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return []string{path}, nil
+		}
+	}
+	return nil, fmt.Errorf("no editor found (set $EDITOR)")
+}
+
+// editMessage opens message in the user's editor and returns the edited
+// text with comment lines removed. It fails if the editor exits non-zero or
+// the message is left empty.
+func editMessage(ctx context.Context, message string) (string, error) {
+	editor, err := editorCommand()
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp("", "arc-ai-COMMIT_EDITMSG-*")
+	if err != nil {
+		return "", fmt.Errorf("create message file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(message + "\n" + editorHint); err != nil {
+		f.Close()
+		return "", fmt.Errorf("write message file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write message file: %w", err)
+	}
+
+	args := append(editor[1:], f.Name())
+	// The editor gets Ctrl-C from the terminal itself, and may use it
+	cmd := exec.CommandContext(context.WithoutCancel(ctx), editor[0], args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	defer inForeground()()
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed, commit aborted: %w", err)
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("read message file: %w", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t\r"))
+		}
+	}
+
+	edited := strings.TrimSpace(strings.Join(lines, "\n"))
+	if edited == "" {
+		return "", &Error{Kind: KindCancelled, Msg: "empty commit message, commit aborted"}
+	}
+	return edited, nil
+}
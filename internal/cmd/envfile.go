@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultEnvFile is loaded from the working directory if it exists.
+const defaultEnvFile = ".env"
+
+// apiKeyVars are the variables loaded from defaultEnvFile when --env-file
+// is not given. A repository's .env could otherwise set variables such as
+// GIT_EXTERNAL_DIFF or LD_PRELOAD, which git and provider CLIs act on, or
+// OLLAMA_HOST, which decides where a diff is sent.
+var apiKeyVars = map[string]bool{
+	"ANTHROPIC_API_KEY": true,
+	"OPENAI_API_KEY":    true,
+	"GEMINI_API_KEY":    true,
+}
+
+var (
+	envKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	envRef = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+)
+
+// loadEnvFile sets the variables in the .env file at path that are not
+// already in the environment. Unless the file was named explicitly, only
+// apiKeyVars are set, and a missing file is not an error.
+func loadEnvFile(path string, explicit bool, log *slog.Logger) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read env file: %w", err)
+	}
+
+	vars, err := parseEnvFile(path, string(data))
+	if err != nil {
+		return err
+	}
+	var skipped []string
+	for _, v := range vars {
+		if !explicit && !apiKeyVars[v[0]] {
+			skipped = append(skipped, v[0])
+			continue
+		}
+		if _, set := os.LookupEnv(v[0]); set {
+			continue
+		}
+		if err := os.Setenv(v[0], v[1]); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if len(skipped) > 0 {
+		log.Debug("only API keys are loaded from the default env file; name it with --env-file to load the rest",
+			"path", path, "skipped", strings.Join(skipped, ","))
+	}
+	return nil
+}
+
+// parseEnvFile parses KEY=VALUE lines, in order. Lines may start with
+// "export"; blank lines and lines starting with # are skipped. Values may
+// be single-quoted, taken literally, or double-quoted, with backslash
+// escapes such as \n and \". Unquoted and double-quoted values expand
+// $VAR and ${VAR} from the environment, or else from earlier lines.
+func parseEnvFile(path, text string) ([][2]string, error) {
+	var vars [][2]string
+	defined := map[string]string{}
+	lookup := func(name string) string {
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		return defined[name]
+	}
+
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !envKey.MatchString(key) {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, i+1)
+		}
+		value, err := envValue(strings.TrimSpace(value), lookup)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+
+		defined[key] = value
+		vars = append(vars, [2]string{key, value})
+	}
+	return vars, nil
+}
+
+// envValue unquotes and expands the value of a .env line.
+func envValue(value string, lookup func(string) string) (string, error) {
+	expand := func(s string) string {
+		return envRef.ReplaceAllStringFunc(s, func(ref string) string {
+			m := envRef.FindStringSubmatch(ref)
+			return lookup(m[1] + m[2])
+		})
+	}
+
+	switch {
+	case strings.HasPrefix(value, "'"):
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated single quote")
+		}
+		return value[1 : end+1], nil
+
+	case strings.HasPrefix(value, `"`):
+		// Escaped characters, such as \$, are not expanded
+		var b, run strings.Builder
+		flush := func() {
+			b.WriteString(expand(run.String()))
+			run.Reset()
+		}
+		for i := 1; i < len(value); i++ {
+			switch c := value[i]; {
+			case c == '"':
+				flush()
+				return b.String(), nil
+			case c == '\\' && i+1 < len(value):
+				flush()
+				i++
+				switch value[i] {
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				default:
+					b.WriteByte(value[i])
+				}
+			default:
+				run.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated double quote")
+	}
+
+	// An unquoted value ends at a comment
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return expand(value), nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// ErrorKind classifies the errors returned by arc-ai commands.
+type ErrorKind int
+
+const (
+	// KindNoProvider means no usable AI provider was found, or the requested
+	// provider is unknown or unavailable.
+	KindNoProvider ErrorKind = iota + 1
+	// KindProviderFailed means the provider was called but the request
+	// failed or timed out.
+	KindProviderFailed
+	// KindNoChanges means there was nothing for the command to describe,
+	// such as an empty staged diff.
+	KindNoChanges
+	// KindNotRepo means the command needs a git repository and was run
+	// outside one.
+	KindNotRepo
+	// KindCancelled means the user declined to go on when asked, such as
+	// to send a large request or to use a commit message.
+	KindCancelled
+)
+
+// Exit codes of the arc-ai process, by outcome. Scripts can tell, say, a
+// commit with nothing staged (ExitNoChanges) from a provider that failed
+// (ExitProviderFailed).
+const (
+	ExitOK             = 0
+	ExitError          = 1 // any failure without a more specific code
+	ExitUsage          = 2 // an unknown command or flag, or missing or extra arguments
+	ExitNoProvider     = 3 // KindNoProvider
+	ExitProviderFailed = 4 // KindProviderFailed
+	ExitNoChanges      = 5 // KindNoChanges
+	ExitNotRepo        = 6 // KindNotRepo
+	ExitCancelled      = 7 // KindCancelled
+	// ExitInterrupted is for Ctrl-C (SIGINT); SIGTERM exits 143, also
+	// 128 plus the signal number, as shells report a killed process.
+	ExitInterrupted = 130
+)
+
+// ExitCode returns the exit code for an error returned by a command: the
+// code for its Kind, or ExitError for an error without one.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		return ExitError
+	}
+	switch e.Kind {
+	case KindNoProvider:
+		return ExitNoProvider
+	case KindProviderFailed:
+		return ExitProviderFailed
+	case KindNoChanges:
+		return ExitNoChanges
+	case KindNotRepo:
+		return ExitNotRepo
+	case KindCancelled:
+		return ExitCancelled
+	}
+	return ExitError
+}
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNoProvider:
+		return "no provider"
+	case KindProviderFailed:
+		return "provider failed"
+	case KindNoChanges:
+		return "no changes"
+	case KindNotRepo:
+		return "not a repository"
+	case KindCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// Error is an error with a Kind that callers can inspect with errors.As.
+// errors.Is reports whether an *Error has the same Kind as the sentinel, so
+// errors.Is(err, ErrNoProvider) holds for any no-provider error regardless
+// of its message.
+type Error struct {
+	Kind ErrorKind
+	// Msg is the human-readable description.
+	Msg string
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is matches any *Error of the same Kind.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind
+}
+
+// fromAIError converts an error from the ai package into an *Error of the
+// matching kind, so that errors.Is(err, ErrNoProvider) holds either way.
+func fromAIError(err error) error {
+	var e *ai.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	kind := KindProviderFailed
+	if e.Kind == ai.KindNoProvider {
+		kind = KindNoProvider
+	}
+	return &Error{Kind: kind, Msg: e.Msg, Err: e.Err}
+}
+
+// Sentinel errors for use with errors.Is.
+var (
+	ErrNoProvider      = &Error{Kind: KindNoProvider, Msg: "no AI provider available"}
+	ErrProviderFailed  = &Error{Kind: KindProviderFailed, Msg: "AI request failed"}
+	ErrNoStagedChanges = &Error{Kind: KindNoChanges, Msg: "no staged changes"}
+	ErrNotRepo         = &Error{Kind: KindNotRepo, Msg: "not a git repository (or any parent directory)"}
+	ErrCancelled       = &Error{Kind: KindCancelled, Msg: "cancelled"}
+)
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// defaultConfirmTokens is the estimated prompt size, in tokens, above which
+// a request needs confirmation.
+const defaultConfirmTokens = 20000
+
+// estimate describes the estimated input size and cost of prompt for the
+// selected model. The cost is included when a rate is configured for it.
+func (o *aiOptions) estimate(prompt string) string {
+	tokens := estimateTokens(prompt)
+	s := fmt.Sprintf("~%d tokens", tokens)
+	model := o.estimateModel()
+	if rate, ok := o.rates[model]; ok {
+		s += fmt.Sprintf(" (~$%.4f at $%.2f per million for %s)", float64(tokens)*rate/1e6, rate, model)
+	}
+	return s
+}
+
+// estimateModel returns the model a request will use, as far as it is
+// known before a provider is picked: with the auto provider and no
+// --model, it is not.
+func (o *aiOptions) estimateModel() string {
+	if o.provider == "" || o.provider == providerAuto {
+		// Only an alias for every provider resolves the same way for all
+		return ai.ResolveModel("", o.model, o.aliases)
+	}
+	if o.model != "" {
+		return ai.ResolveModel(o.provider, o.model, o.aliases)
+	}
+	if m := o.models[o.provider]; m != "" {
+		return ai.ResolveModel(o.provider, m, o.aliases)
+	}
+	return ai.DefaultModel(o.provider)
+}
+
+// preflight runs before prompt is sent. With --estimate-only it prints the
+// estimate and returns false. If the estimate exceeds the confirm-tokens
+// threshold it asks on reader whether to continue, unless --yes was given,
+// and returns false with a KindCancelled error if the user declines.
+func (o *aiOptions) preflight(reader *bufio.Reader, prompt string) (bool, error) {
+	if o.estimateOnly {
+		fmt.Printf("Estimated input: %s\n", o.estimate(prompt))
+		return false, nil
+	}
+	if o.yes || o.confirmTokens <= 0 || estimateTokens(prompt) <= o.confirmTokens {
+		return true, nil
+	}
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("this request is large: %s; pass --yes to send it without confirmation", o.estimate(prompt))
+	}
+
+	fmt.Fprintf(os.Stderr, "This request is large: %s.\nSend it? [y/N]: ", o.estimate(prompt))
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer != "y" && answer != "yes" {
+		return false, &Error{Kind: KindCancelled, Msg: "request not sent"}
+	}
+	return true, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// explanation is the structured form of an explain response.
+type explanation struct {
+	Summary string         `json:"summary"`
+	Symbols []symbolDetail `json:"symbols"`
+	Notes   []string       `json:"notes"`
+}
+
+type symbolDetail struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func newExplainCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var focus string
+	var maxTokens int
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "explain [file]",
+		Short: "Explain what code does",
+		Long: `Explain what a piece of code does.
+
+The code is read from the file argument, or from stdin if none is given.
+Use --focus to narrow the explanation to a specific symbol.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			code, err := readFileOrStdin(path)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(code) == "" {
+				return fmt.Errorf("no code provided")
+			}
+
+			code = truncateText(code, maxTokens)
+
+			var header strings.Builder
+			if path != "" {
+				fmt.Fprintf(&header, "File: %s\n", path)
+			}
+			if focus != "" {
+				fmt.Fprintf(&header, "Focus on %s and what it depends on; mention the rest only as needed.\n", focus)
+			}
+
+			if out.Is(output.OutputJSON) {
+				prompt := fmt.Sprintf(`Explain what the following code does.
+%sRespond with ONLY a JSON object, no explanations, with:
+- "summary": a short paragraph describing the code's purpose
+- "symbols": an array of {"name", "description"} for the important functions and types
+- "notes": an array of strings for caveats, bugs, or notable behavior
+
+Code:
+%s`, header.String(), code)
+
+				if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+					return err
+				}
+
+				response, err := askAI(ctx, aiOpts.request(prompt))
+				if err != nil {
+					return err
+				}
+
+				result := explanation{Symbols: []symbolDetail{}, Notes: []string{}}
+				if err := decodeResponseJSON(response, &result); err != nil {
+					return err
+				}
+				return output.JSON(result)
+			}
+
+			prompt := fmt.Sprintf(`Explain what the following code does. Start with a short summary of its
+purpose, then walk through the important parts. Be concise.
+%s
+Code:
+%s`, header.String(), code)
+
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+				return err
+			}
+
+			response, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(response)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringVar(&focus, "focus", "", "Symbol to focus the explanation on")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the code sent to the AI")
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
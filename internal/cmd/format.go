@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats handled in this package. The output package only knows
+// table and JSON, so commands that offer these check for them before
+// resolving the other formats.
+const (
+	outputYAML = "yaml"
+	// outputRaw prints only the response text, byte for byte.
+	outputRaw = "raw"
+)
+
+// resolveOutput validates --output like out.Resolve, additionally accepting
+// the extra formats. It returns the extra format requested, or "" if out
+// was resolved to one of its own formats.
+func resolveOutput(cmd *cobra.Command, out *output.OutputOptions, extra ...string) (string, error) {
+	if f := cmd.Flags().Lookup("output"); f != nil {
+		for _, e := range extra {
+			if f.Value.String() == e {
+				return e, nil
+			}
+		}
+	}
+	return "", out.Resolve()
+}
+
+// writeYAML writes v to stdout as YAML, the counterpart of output.JSON.
+func writeYAML(v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// emptyTree is the hash of git's empty tree object, used to diff a root commit.
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// git runs a git command and returns its trimmed stdout.
+func git(ctx context.Context, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// requireRepo returns ErrNotRepo unless the working directory is inside a
+// git working tree, so that commands fail with a clear message rather than
+// the error of whichever git command runs first.
+func requireRepo(ctx context.Context) error {
+	out, err := git(ctx, "rev-parse", "--is-inside-work-tree")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) || err == nil && out != "true" {
+		return ErrNotRepo
+	}
+	return err
+}
+
+// gitDiff returns the staged diff, or the working-tree diff if staged is false,
+// formatted as f says. It is limited to paths, if any are given, and paths
+// matching any of the exclude globs are left out.
+func gitDiff(ctx context.Context, staged bool, f diffFormat, paths, exclude []string) (string, error) {
+	args := append([]string{"diff"}, f.args()...)
+	if staged {
+		args = append(args, "--cached")
+	}
+	args = append(args, pathspecs(paths, exclude)...)
+
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// trackedDiff returns the changes to tracked files, staged or not, relative
+// to HEAD: what 'git commit -a' would commit. It is formatted, limited, and
+// filtered like gitDiff.
+func trackedDiff(ctx context.Context, f diffFormat, paths, exclude []string) (string, error) {
+	base := "HEAD"
+	if _, err := git(ctx, "rev-parse", "--verify", "HEAD"); err != nil {
+		// No commits yet
+		base = emptyTree
+	}
+
+	args := append([]string{"diff"}, f.args()...)
+	args = append(args, base)
+	args = append(args, pathspecs(paths, exclude)...)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// amendDiff returns the change that amending HEAD with the staged changes
+// would produce: HEAD's own changes combined with what is staged, or with
+// all changes to tracked files if all is set. It is formatted, limited, and
+// filtered like gitDiff.
+func amendDiff(ctx context.Context, all bool, f diffFormat, paths, exclude []string) (string, error) {
+	if _, err := git(ctx, "rev-parse", "--verify", "HEAD"); err != nil {
+		return "", fmt.Errorf("no commit to amend")
+	}
+
+	base := "HEAD^"
+	if _, err := git(ctx, "rev-parse", "--verify", "HEAD^"); err != nil {
+		// HEAD is a root commit
+		base = emptyTree
+	}
+
+	args := append([]string{"diff"}, f.args()...)
+	if !all {
+		args = append(args, "--cached")
+	}
+	args = append(args, base)
+	args = append(args, pathspecs(paths, exclude)...)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(out), nil
+}
+
+// diffAlgorithms are the values git diff accepts for --diff-algorithm.
+var diffAlgorithms = []string{"myers", "minimal", "patience", "histogram"}
+
+// diffFormat controls how gitDiff and its variants show changes.
+type diffFormat struct {
+	// recurseSubmodules shows the diff of the files inside changed
+	// submodules rather than the subjects of the commits they moved by,
+	// which say more than the bare commit hashes git shows by default.
+	recurseSubmodules bool
+	// algorithm is one of diffAlgorithms, or "" for git's configured one.
+	algorithm string
+}
+
+// args returns the git diff options for f.
+func (f diffFormat) args() []string {
+	args := []string{"--submodule=log"}
+	if f.recurseSubmodules {
+		args = []string{"--submodule=diff"}
+	}
+	if f.algorithm != "" {
+		args = append(args, "--diff-algorithm="+f.algorithm)
+	}
+	return args
+}
+
+// checkDiffAlgorithm returns an error unless name is one of
+// diffAlgorithms or "".
+func checkDiffAlgorithm(name string) error {
+	if name == "" {
+		return nil
+	}
+	for _, a := range diffAlgorithms {
+		if a == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown --diff-algorithm %q (valid: %s)", name, strings.Join(diffAlgorithms, ", "))
+}
+
+// pathspecs turns paths to include and globs to exclude into git pathspec
+// arguments. Paths are relative to the current directory, as on the
+// command line. The exclude globs, and the whole-tree ":/" pathspec used
+// when there are no paths, are anchored at the top of the working tree so
+// they do not depend on the current directory.
+func pathspecs(paths, exclude []string) []string {
+	if len(paths) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	args := []string{"--"}
+	if len(paths) == 0 {
+		args = append(args, ":/")
+	}
+	args = append(args, paths...)
+	for _, g := range exclude {
+		args = append(args, ":(top,exclude)"+g)
+	}
+	return args
+}
+
+// checkPaths returns an error for the first path that neither exists nor
+// is known to git, so that a typo does not silently select nothing. Paths
+// deleted in the index or HEAD are known to git.
+func checkPaths(ctx context.Context, paths []string) error {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			continue
+		}
+		if _, err := git(ctx, "ls-files", "--error-unmatch", "--with-tree=HEAD", "--", p); err == nil {
+			continue
+		}
+		return fmt.Errorf("path %s does not exist", p)
+	}
+	return nil
+}
+
+// headSummary returns the abbreviated hash and subject of HEAD.
+func headSummary(ctx context.Context) (string, error) {
+	return git(ctx, "log", "-1", "--format=%h %s")
+}
+
+// commitTemplatePath resolves the commit message template: the configured
+// commit.template, or a .gitmessage file at the root of the working tree.
+// Relative paths are resolved against the working tree. It returns "" if
+// there is no template.
+func commitTemplatePath(ctx context.Context) (string, error) {
+	root, err := git(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+
+	path, err := git(ctx, "config", "--path", "commit.template")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// Not configured
+		path = ""
+	} else if err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		path = filepath.Join(root, ".gitmessage")
+		if _, err := os.Stat(path); err != nil {
+			return "", nil
+		}
+		return path, nil
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	return path, nil
+}
+
+// commitTemplate returns the contents of the commit message template, or ""
+// if none is configured.
+func commitTemplate(ctx context.Context) (string, error) {
+	path, err := commitTemplatePath(ctx)
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read commit template: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// currentBranch returns the name of the checked-out branch, or "HEAD" when
+// detached.
+func currentBranch(ctx context.Context) (string, error) {
+	return git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// signOffTrailer returns a DCO "Signed-off-by" trailer for the configured
+// git identity.
+func signOffTrailer(ctx context.Context) (string, error) {
+	name, _ := git(ctx, "config", "user.name")
+	email, _ := git(ctx, "config", "user.email")
+	if name == "" || email == "" {
+		return "", fmt.Errorf("--sign-off requires git config user.name and user.email to be set")
+	}
+	return fmt.Sprintf("Signed-off-by: %s <%s>", name, email), nil
+}
+
+// commitLog returns the full messages of the commits in rng, newest first.
+func commitLog(ctx context.Context, rng string) ([]string, error) {
+	out, err := git(ctx, "log", "--format=%B%x1e", rng)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []string
+	for _, msg := range strings.Split(out, "\x1e") {
+		if msg = strings.TrimSpace(msg); msg != "" {
+			commits = append(commits, msg)
+		}
+	}
+	return commits, nil
+}
+
+// recentSubjects returns the subjects of up to n commits reachable from
+// HEAD, newest first, after skipping the first skip. It returns nil in a
+// repository with no commits.
+func recentSubjects(ctx context.Context, n, skip int) ([]string, error) {
+	if _, err := git(ctx, "rev-parse", "--verify", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	out, err := git(ctx, "log", fmt.Sprintf("--max-count=%d", n), fmt.Sprintf("--skip=%d", skip), "--format=%s")
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// headMessage returns the full commit message of HEAD.
+func headMessage(ctx context.Context) (string, error) {
+	return git(ctx, "log", "-1", "--format=%B")
+}
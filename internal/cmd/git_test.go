@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// chdir changes the working directory to dir for the rest of the test.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	old, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(old) })
+}
+
+// runGit runs git in dir, failing the test if it fails.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// isolateGit keeps the user's git config out of the test.
+func isolateGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	for _, v := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(v, "Test")
+	}
+	for _, v := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(v, "test@example.com")
+	}
+}
+
+func TestLinkedWorktree(t *testing.T) {
+	isolateGit(t)
+	// Resolved, as git reports paths, in case TMPDIR is a symlink
+	tmp, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	main := filepath.Join(tmp, "main")
+	linked := filepath.Join(tmp, "linked")
+
+	runGit(t, tmp, "init", "-q", main)
+	writeFiles(t, main, map[string]string{"README": "hello\n"})
+	runGit(t, main, "add", "README")
+	runGit(t, main, "commit", "-q", "-m", "initial")
+	runGit(t, main, "worktree", "add", "-q", "-b", "feature", linked)
+
+	writeFiles(t, linked, map[string]string{"feature.go": "package feature\n"})
+	runGit(t, linked, "add", "feature.go")
+	chdir(t, linked)
+	ctx := context.Background()
+
+	if err := requireRepo(ctx); err != nil {
+		t.Fatalf("requireRepo in a linked worktree: %v", err)
+	}
+	diff, err := gitDiff(ctx, true, diffFormat{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "+++ b/feature.go") {
+		t.Errorf("the staged diff of the linked worktree is missing feature.go:\n%s", diff)
+	}
+
+	// Hooks are shared by every worktree of the repository
+	path, err := hookPath(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(main, ".git", "hooks", hookName); path != want {
+		t.Errorf("hookPath = %s, want %s", path, want)
+	}
+
+	// A relative core.hooksPath is relative to the top of each worktree,
+	// even from a subdirectory
+	runGit(t, main, "config", "core.hooksPath", ".githooks")
+	writeFiles(t, linked, map[string]string{"sub/x.go": "package sub\n"})
+	chdir(t, filepath.Join(linked, "sub"))
+	path, err = hookPath(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(linked, ".githooks", hookName); path != want {
+		t.Errorf("hookPath with core.hooksPath = %s, want %s", path, want)
+	}
+
+	chdir(t, main)
+	diff, err = gitDiff(ctx, true, diffFormat{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != "" {
+		t.Errorf("the main worktree has the linked one's staged changes:\n%s", diff)
+	}
+}
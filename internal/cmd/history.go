@@ -0,0 +1,175 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// historyQuestionWidth is how much of a session's first question the
+// history list shows.
+const historyQuestionWidth = 60
+
+// historyEntry summarizes a session for `arc-ai history --output json`.
+type historyEntry struct {
+	ID        string    `json:"id"`
+	Started   time.Time `json:"started"`
+	Exchanges int       `json:"exchanges"`
+	Question  string    `json:"question"`
+}
+
+func newHistoryCmd() *cobra.Command {
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List past ask conversations",
+		Long: `List the conversations kept by 'arc-ai ask', newest first, with the
+time each started and its first question.
+
+'arc-ai history show <id>' prints a whole conversation, and
+'arc-ai history clear' deletes them all.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			sessions, err := historySessions()
+			if err != nil {
+				return err
+			}
+
+			if out.Is(output.OutputJSON) {
+				entries := []historyEntry{}
+				for _, s := range sessions {
+					entries = append(entries, historyEntry{
+						ID:        s.ID,
+						Started:   s.started(),
+						Exchanges: len(s.Exchanges),
+						Question:  s.question(),
+					})
+				}
+				return output.JSON(entries)
+			}
+
+			if len(sessions) == 0 {
+				fmt.Println("No conversations yet.")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tSTARTED\tEXCHANGES\tQUESTION")
+			for _, s := range sessions {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", s.ID, s.started().Format("2006-01-02 15:04"),
+					len(s.Exchanges), truncateLine(s.question(), historyQuestionWidth))
+			}
+			return w.Flush()
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	cmd.AddCommand(newHistoryShowCmd())
+	cmd.AddCommand(newHistoryClearCmd())
+	return cmd
+}
+
+func newHistoryShowCmd() *cobra.Command {
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Print a past ask conversation",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			s, err := historySession(args[0])
+			if err != nil {
+				return err
+			}
+			if out.Is(output.OutputJSON) {
+				return output.JSON(s)
+			}
+
+			for i, ex := range s.Exchanges {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("[%s] Question:\n%s\n\nResponse:\n%s\n", ex.Time.Format("2006-01-02 15:04:05"), ex.Question, ex.Response)
+			}
+			return nil
+		},
+	}
+
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+	cmd.ValidArgsFunction = completeSessionIDs
+	return cmd
+}
+
+func newHistoryClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Delete every past ask conversation",
+		Long: `Delete the kept conversations, including the one 'arc-ai ask --continue'
+would follow up on.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := clearHistory(); err != nil {
+				return err
+			}
+			fmt.Println("History cleared.")
+			return nil
+		},
+	}
+}
+
+// completeSessionIDs completes `history show` with the IDs in the history.
+func completeSessionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sessions, err := historySessions()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var ids []string
+	for _, s := range sessions {
+		ids = append(ids, s.ID+"\t"+truncateLine(s.question(), historyQuestionWidth))
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// question returns the session's first question, or "" if it has none.
+func (s *session) question() string {
+	if len(s.Exchanges) == 0 {
+		return ""
+	}
+	return s.Exchanges[0].Question
+}
+
+// truncateLine returns the first line of s, cut to at most n characters
+// with an ellipsis.
+func truncateLine(s string, n int) string {
+	line, _, more := strings.Cut(strings.TrimSpace(s), "\n")
+	r := []rune(line)
+	if len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	if more {
+		return line + " …"
+	}
+	return line
+}
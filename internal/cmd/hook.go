@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/internal/fileutil"
+)
+
+// hookName is the git hook arc-ai installs.
+const hookName = "prepare-commit-msg"
+
+// hookMarker identifies a hook written by arc-ai, so it can be replaced or
+// removed without --force.
+const hookMarker = "# Installed by arc-ai hook install."
+
+// hookScript fills in the commit message buffer before the editor opens.
+// Messages given with -m, -F, or --amend, merges, squashes, and messages
+// already filled in are left alone, as are rebases and merges in progress.
+// If arc-ai is missing or fails, the commit proceeds with an empty message.
+const hookScript = `#!/bin/sh
+` + hookMarker + `
+# Fills in the commit message with one generated from the staged changes.
+
+msg_file="$1"
+
+# Only plain 'git commit', not -m, -F, --amend, merges, or squashes
+[ -z "$2" ] || exit 0
+
+# The message already has content, e.g. from a template
+grep -q '^[^#[:space:]]' "$msg_file" && exit 0
+
+git_dir=$(git rev-parse --git-dir) || exit 0
+for f in rebase-merge rebase-apply MERGE_HEAD CHERRY_PICK_HEAD REVERT_HEAD; do
+	[ -e "$git_dir/$f" ] && exit 0
+done
+
+command -v arc-ai >/dev/null 2>&1 || exit 0
+
+echo "arc-ai: generating commit message..." >&2
+message=$(arc-ai commit --dry-run --quiet </dev/null) || exit 0
+[ -n "$message" ] || exit 0
+
+# Keep git's comments below the generated message
+{ printf '%s\n' "$message"; cat "$msg_file"; } >"$msg_file.arc-ai" &&
+	mv "$msg_file.arc-ai" "$msg_file"
+`
+
+func newHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook",
+		Short: "Manage the git hook that fills in commit messages",
+		Long: `Install or remove a prepare-commit-msg git hook that fills in the
+message buffer with 'arc-ai commit --dry-run' whenever you run a plain
+'git commit'. The generated message can be edited as usual before the
+commit is made.
+
+The hook is skipped for commits that already have a message (-m, -F,
+--amend, templates) and while a rebase or merge is in progress.`,
+	}
+
+	cmd.AddCommand(newHookInstallCmd())
+	cmd.AddCommand(newHookUninstallCmd())
+	return cmd
+}
+
+func newHookInstallCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the prepare-commit-msg hook",
+		Long: `Install the prepare-commit-msg hook in the current repository.
+
+An existing hook that was not installed by arc-ai is left in place unless
+--force is given. Installing again replaces arc-ai's own hook.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := hookPath(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			ours, err := checkHook(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			if err == nil && !ours && !force {
+				return fmt.Errorf("%s already exists and was not installed by arc-ai; use --force to replace it", path)
+			}
+
+			if err := fileutil.WriteAtomic(path, []byte(hookScript)); err != nil {
+				return fmt.Errorf("write hook: %w", err)
+			}
+			if err := os.Chmod(path, 0o755); err != nil {
+				return fmt.Errorf("write hook: %w", err)
+			}
+
+			fmt.Printf("Installed %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing hook that was not installed by arc-ai")
+	return cmd
+}
+
+func newHookUninstallCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove the prepare-commit-msg hook",
+		Long: `Remove the prepare-commit-msg hook installed by 'arc-ai hook install'.
+
+A hook that was not installed by arc-ai is left in place unless --force
+is given.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := hookPath(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			ours, err := checkHook(path)
+			if errors.Is(err, os.ErrNotExist) {
+				fmt.Println("No prepare-commit-msg hook is installed.")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if !ours && !force {
+				return fmt.Errorf("%s was not installed by arc-ai; use --force to remove it", path)
+			}
+
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("remove hook: %w", err)
+			}
+
+			fmt.Printf("Removed %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Remove the hook even if it was not installed by arc-ai")
+	return cmd
+}
+
+// hookPath returns where git looks for the prepare-commit-msg hook,
+// honoring core.hooksPath and worktrees.
+func hookPath(ctx context.Context) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := requireRepo(ctx); err != nil {
+		return "", err
+	}
+	path, err := git(ctx, "rev-parse", "--git-path", "hooks/"+hookName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(path)
+}
+
+// checkHook reports whether the hook at path was installed by arc-ai. The
+// error wraps os.ErrNotExist if there is no hook.
+func checkHook(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("read hook: %w", err)
+	}
+	return strings.Contains(string(data), hookMarker), nil
+}
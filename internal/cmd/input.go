@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// readFileOrStdin returns the contents of path, or of stdin if path is ""
+// or "-".
+func readFileOrStdin(path string) (string, error) {
+	if path == "" || path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("read stdin: %w", err)
+		}
+		return string(data), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe, file, or /dev/null. Any other character device counts as a
+// terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null is a character device too
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+// stdinIsTerminal reports whether questions can be asked on stdin. When it
+// cannot, commands proceed only with --yes rather than wait for an answer
+// that will never come.
+func stdinIsTerminal() bool { return isTerminal(os.Stdin) }
+
+// stdoutIsTerminal reports whether stdout is shown to a person, who needs
+// progress messages, rather than captured by a script, which does not.
+func stdoutIsTerminal() bool { return isTerminal(os.Stdout) }
@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultLang is the response language when --lang is not set.
+const defaultLang = "English"
+
+// isEnglish reports whether lang names English, as a name or BCP-47 tag.
+func isEnglish(lang string) bool {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	return lang == "" || lang == "english" || lang == "en" || strings.HasPrefix(lang, "en-") || strings.HasPrefix(lang, "en_")
+}
+
+// languageRule returns a prompt instruction to write the named thing (e.g.
+// "the message") in lang, or "" for English, which the prompts assume.
+func languageRule(what, lang string) string {
+	if isEnglish(lang) {
+		return ""
+	}
+	return fmt.Sprintf("Write %s in the language %q.", what, lang)
+}
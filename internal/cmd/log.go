@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// logLevels are the values of --log-level.
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+// parseLogLevel parses a --log-level value.
+func parseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown --log-level %q (valid: %s)", s, strings.Join(logLevels, ", "))
+	}
+	return level, nil
+}
+
+// newLogger returns a logger to stderr, keeping stdout for command output,
+// at the root --log-level, or debug with --verbose unless --log-level is
+// given too.
+func newLogger(cmd *cobra.Command) *slog.Logger {
+	level := slog.LevelWarn
+	if f := cmd.Flags().Lookup("log-level"); f != nil && f.Changed {
+		// Checked before the command runs
+		level, _ = parseLogLevel(f.Value.String())
+	} else if v, err := cmd.Flags().GetBool("verbose"); err == nil && v {
+		level = slog.LevelDebug
+	}
+	return slog.New(newCLIHandler(os.Stderr, level))
+}
+
+// cliHandler is a slog.Handler that writes each record as one line for a
+// person to read: "arc-ai: level: message key=value ...".
+type cliHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	// attrs are the formatted attributes from WithAttrs.
+	attrs string
+	// prefix qualifies the keys of later attributes with WithGroup's names.
+	prefix string
+}
+
+func newCLIHandler(w io.Writer, level slog.Leveler) *cliHandler {
+	return &cliHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *cliHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *cliHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "arc-ai: %s: %s", strings.ToLower(r.Level.String()), r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.prefix, a)
+		return true
+	})
+	b.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	for _, a := range attrs {
+		appendAttr(&b, h.prefix, a)
+	}
+	h2 := *h
+	h2.attrs += b.String()
+	return &h2
+}
+
+func (h *cliHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix += name + "."
+	return &h2
+}
+
+// appendAttr writes a as " key=value", quoting values with spaces.
+func appendAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range a.Value.Group() {
+			appendAttr(b, prefix, g)
+		}
+		return
+	}
+	v := a.Value.String()
+	if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	fmt.Fprintf(b, " %s%s=%s", prefix, a.Key, v)
+}
+
+// logUsage returns an ai.Middleware that logs the provider, model,
+// duration, and token usage of each answered request at info level.
+func logUsage(log *slog.Logger) ai.Middleware {
+	return func(next ai.Handler) ai.Handler {
+		return func(ctx context.Context, req ai.Request) (ai.Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, err
+			}
+			attrs := []any{"provider", resp.Provider}
+			if resp.Model != "" {
+				attrs = append(attrs, "model", resp.Model)
+			}
+			attrs = append(attrs,
+				"duration", time.Since(start).Round(time.Millisecond),
+				"input_tokens", resp.Usage.InputTokens,
+				"output_tokens", resp.Usage.OutputTokens)
+			if resp.Usage.Estimated {
+				attrs = append(attrs, "estimated", true)
+			}
+			if resp.Cached {
+				attrs = append(attrs, "cached", true)
+			}
+			log.InfoContext(ctx, "answered", attrs...)
+			return resp, nil
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ANSI styles used to render markdown.
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiItalic    = "\033[3m"
+	ansiUnderline = "\033[4m"
+	ansiGreen     = "\033[32m"
+	ansiYellow    = "\033[33m"
+	ansiMagenta   = "\033[35m"
+	ansiCyan      = "\033[36m"
+	ansiGray      = "\033[90m"
+)
+
+var (
+	mdHeading    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBullet     = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	mdNumbered   = regexp.MustCompile(`^(\s*)(\d+[.)])\s+`)
+	mdQuote      = regexp.MustCompile(`^\s*>\s?`)
+	mdRule       = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	mdBold       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic     = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*)\*`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdInlineCode = regexp.MustCompile("`[^`]+`")
+)
+
+// renderMarkdown styles markdown with ANSI escapes for a terminal:
+// headings, bold and italic text, inline code, links, lists, quotes, rules,
+// and code blocks with their syntax highlighted.
+func renderMarkdown(s string) string {
+	var out []string
+	fence, lang := "", ""
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				out = append(out, ansiGray+line+ansiReset)
+				continue
+			}
+			out = append(out, highlightCode(line, lang))
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			lang = strings.ToLower(strings.TrimSpace(strings.TrimLeft(trimmed, "`~")))
+			out = append(out, ansiGray+line+ansiReset)
+		case preformatted(line) && !strings.HasPrefix(trimmed, "|"):
+			out = append(out, highlightCode(line, ""))
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			style := ansiBold
+			if len(m[1]) == 1 {
+				style += ansiUnderline
+			}
+			out = append(out, style+ansiMagenta+renderInline(m[2], style+ansiMagenta)+ansiReset)
+		case mdRule.MatchString(line):
+			out = append(out, ansiGray+strings.Repeat("─", 40)+ansiReset)
+		case mdQuote.MatchString(line):
+			rest := line[len(mdQuote.FindString(line)):]
+			out = append(out, ansiGray+"│ "+ansiReset+ansiItalic+renderInline(rest, ansiItalic)+ansiReset)
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			rest := line[len(m[0]):]
+			// "• " keeps the width of "- ", so wrapped lines still line up
+			out = append(out, m[1]+ansiCyan+"•"+ansiReset+strings.Repeat(" ", len(m[0])-len(m[1])-1)+renderInline(rest, ""))
+		case mdNumbered.MatchString(line):
+			m := mdNumbered.FindStringSubmatch(line)
+			rest := line[len(m[0]):]
+			out = append(out, m[1]+ansiCyan+m[2]+ansiReset+m[0][len(m[1])+len(m[2]):]+renderInline(rest, ""))
+		default:
+			out = append(out, renderInline(line, ""))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderInline styles the inline markup in text. base is the style in
+// effect around it, restored after each styled span.
+func renderInline(text, base string) string {
+	restore := ansiReset + base
+
+	// Inline code is literal, so style around it rather than inside it
+	var b strings.Builder
+	last := 0
+	for _, loc := range mdInlineCode.FindAllStringIndex(text, -1) {
+		b.WriteString(renderEmphasis(text[last:loc[0]], restore))
+		b.WriteString(ansiCyan + text[loc[0]+1:loc[1]-1] + restore)
+		last = loc[1]
+	}
+	b.WriteString(renderEmphasis(text[last:], restore))
+	return b.String()
+}
+
+func renderEmphasis(text, restore string) string {
+	text = mdLink.ReplaceAllString(text, ansiUnderline+"$1"+restore+" "+ansiGray+"($2)"+restore)
+	text = mdBold.ReplaceAllString(text, ansiBold+"$1$2"+restore)
+	return mdItalic.ReplaceAllString(text, "$1"+ansiItalic+"$2"+restore)
+}
+
+// codeKeywords are the keywords highlighted in code blocks, by language.
+var codeKeywords = map[string][]string{
+	"go": {"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+		"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range",
+		"return", "select", "struct", "switch", "type", "var", "nil", "true", "false"},
+	"python": {"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del",
+		"elif", "else", "except", "finally", "for", "from", "if", "import", "in", "is", "lambda",
+		"not", "or", "pass", "raise", "return", "try", "while", "with", "yield", "None", "True", "False"},
+	"javascript": {"async", "await", "break", "case", "catch", "class", "const", "continue", "default",
+		"else", "export", "extends", "for", "function", "if", "import", "let", "new", "return",
+		"switch", "this", "throw", "try", "typeof", "var", "while", "null", "undefined", "true", "false"},
+	"rust": {"as", "break", "const", "continue", "else", "enum", "fn", "for", "if", "impl", "in",
+		"let", "loop", "match", "mod", "mut", "pub", "return", "self", "struct", "trait", "use",
+		"where", "while", "true", "false"},
+	"sh": {"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if",
+		"in", "local", "return", "then", "while"},
+}
+
+// codeLanguages maps fence info strings to codeKeywords entries.
+var codeLanguages = map[string]string{
+	"go": "go", "golang": "go",
+	"py": "python", "python": "python",
+	"js": "javascript", "javascript": "javascript", "ts": "javascript", "typescript": "javascript",
+	"rs": "rust", "rust": "rust",
+	"sh": "sh", "bash": "sh", "shell": "sh", "zsh": "sh",
+}
+
+var codeToken = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"?|'(?:[^'\\\\]|\\\\.)*'?|`[^`]*`?|\\b\\d[\\d._xXa-fA-F]*\\b|[A-Za-z_]\\w*|//.*|#.*")
+
+// highlightCode colors one line of code: keywords of lang, strings,
+// numbers, and comments. Constructs spanning lines, such as block
+// comments, are not recognized.
+func highlightCode(line, lang string) string {
+	lang = codeLanguages[lang]
+	keywords := map[string]bool{}
+	for _, k := range codeKeywords[lang] {
+		keywords[k] = true
+	}
+	hashComments := lang == "python" || lang == "sh" || lang == ""
+
+	return codeToken.ReplaceAllStringFunc(line, func(tok string) string {
+		switch c := tok[0]; {
+		case strings.HasPrefix(tok, "//") && lang != "python" && lang != "sh":
+			return ansiGray + tok + ansiReset
+		case c == '#' && hashComments:
+			return ansiGray + tok + ansiReset
+		case c == '"' || c == '\'' || c == '`':
+			return ansiGreen + tok + ansiReset
+		case c >= '0' && c <= '9':
+			return ansiYellow + tok + ansiReset
+		case keywords[tok]:
+			return ansiMagenta + tok + ansiReset
+		}
+		return tok
+	})
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultSubjectMax is the default longest subject line, in characters.
+const defaultSubjectMax = 72
+
+// conventionalTypes are the commit types lintMessage accepts in the
+// conventional style.
+var conventionalTypes = []string{
+	"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert",
+}
+
+// typePrefix matches a conventional type prefix in any case, with or
+// without a space after the colon, for normalizing the prefixes of
+// generated messages.
+var typePrefix = regexp.MustCompile(`^(?i)([a-z]+)(\([^)]*\))?(!?):[ \t]*`)
+
+// typeAliases map words models write as a type to the conventional type.
+var typeAliases = map[string]string{
+	"feature":  "feat",
+	"features": "feat",
+	"bugfix":   "fix",
+	"hotfix":   "fix",
+	"doc":      "docs",
+	"tests":    "test",
+}
+
+// trailerLine matches a git trailer such as "Refs: ABC-123".
+var trailerLine = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: `)
+
+// appendTrailer adds trailer to the end of message. If the message already
+// ends in a trailer block the trailer joins it; otherwise it is separated
+// from the body by a blank line. A trailer that is already present is not
+// added again.
+func appendTrailer(message, trailer string) string {
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+
+	lines := strings.Split(last, "\n")
+	for _, line := range lines {
+		if line == trailer {
+			return message
+		}
+	}
+
+	// The subject line is never a trailer block
+	if len(paragraphs) > 1 && allTrailers(lines) {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
+}
+
+func allTrailers(lines []string) bool {
+	for _, line := range lines {
+		if !trailerLine.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// lintMessage checks a commit message against the rules for style and
+// returns a description of each violation, or nil if there are none. Every
+// style limits the subject to subjectMax characters and needs a blank
+// line between the subject and the body; the conventional style also needs
+// a known type prefix.
+func lintMessage(message, style string, subjectMax int) []string {
+	lines := strings.Split(strings.TrimRight(message, "\n"), "\n")
+	subject := lines[0]
+	if strings.TrimSpace(subject) == "" {
+		return []string{"the subject line is empty"}
+	}
+
+	var violations []string
+	if n := utf8.RuneCountInString(subject); n > subjectMax {
+		violations = append(violations, fmt.Sprintf("the subject line is %d characters, more than %d", n, subjectMax))
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		violations = append(violations, "there is no blank line between the subject and the body")
+	}
+
+	if style == styleConventional {
+		m := conventionalPrefix.FindStringSubmatch(subject)
+		switch {
+		case m == nil:
+			violations = append(violations, "the subject does not start with a type prefix such as \"feat: \" or \"fix(scope): \"")
+		case !slices.Contains(conventionalTypes, m[1]):
+			violations = append(violations, fmt.Sprintf("%q is not a conventional commit type (valid: %s)", m[1], strings.Join(conventionalTypes, ", ")))
+		case strings.TrimSpace(subject[len(m[0]):]) == "":
+			violations = append(violations, "the subject has no description after the type prefix")
+		}
+	}
+	return violations
+}
+
+// splitMessage splits a commit message into its subject line and body. The
+// body is everything after the blank line that follows the subject, with
+// surrounding blank lines removed.
+func splitMessage(message string) (subject, body string) {
+	subject, body, _ = strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body)
+}
+
+// joinMessage is the inverse of splitMessage.
+func joinMessage(subject, body string) string {
+	if body == "" {
+		return subject
+	}
+	return subject + "\n\n" + body
+}
+
+// splitTrailers separates a trailer block, such as Signed-off-by lines,
+// from the end of a commit body. Both parts are "" if absent.
+func splitTrailers(body string) (text, trailers string) {
+	paragraphs := strings.Split(body, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+	if last == "" || !allTrailers(strings.Split(last, "\n")) {
+		return body, ""
+	}
+	return strings.TrimSpace(strings.Join(paragraphs[:len(paragraphs)-1], "\n\n")), last
+}
+
+// prefixType returns the conventional type that word, the type of a
+// prefix in any case, names, or "" if it names none.
+func prefixType(word string) string {
+	word = strings.ToLower(word)
+	if t, ok := typeAliases[word]; ok {
+		return t
+	}
+	if slices.Contains(conventionalTypes, word) {
+		return word
+	}
+	return ""
+}
+
+// collapseTypePrefixes leaves message with one well-formed conventional
+// type prefix if it starts with any. A run of them, such as
+// "feat: feat(cmd): add x", is reduced to the last, which is the closest
+// to the description: "feat(cmd): add x". The kept type is lowercased or
+// replaced by the type it is an alias for, and the space after its colon
+// put back if missing. A message whose first word is not a known type is
+// returned unchanged.
+func collapseTypePrefixes(message string) string {
+	rest := message
+	var typ, scope, breaking string
+	for {
+		m := typePrefix.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		t := prefixType(m[1])
+		if t == "" {
+			break
+		}
+		typ, scope, breaking = t, m[2], m[3]
+		rest = rest[len(m[0]):]
+	}
+	if typ == "" {
+		return message
+	}
+	return typ + scope + breaking + ": " + rest
+}
+
+// messageParts is a commit message split into its components, for
+// commit --output json. Type and Scope are empty unless the subject has a
+// conventional prefix.
+type messageParts struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Type    string `json:"type"`
+	Scope   string `json:"scope"`
+}
+
+// parseMessage splits message into its subject, body, and conventional
+// type and scope.
+func parseMessage(message string) messageParts {
+	var p messageParts
+	p.Subject, p.Body = splitMessage(message)
+	if m := conventionalPrefix.FindStringSubmatch(p.Subject); m != nil {
+		p.Type = m[1]
+		p.Scope = strings.Trim(m[2], "()")
+	}
+	return p
+}
+
+// shortenSubject cuts the subject line of message to at most max
+// characters, at the last word boundary that fits, and drops punctuation
+// left dangling at the cut. A first word longer than max is cut mid-word.
+func shortenSubject(message string, max int) string {
+	subject, rest, hasRest := strings.Cut(message, "\n")
+	runes := []rune(subject)
+	if len(runes) <= max {
+		return message
+	}
+
+	cut := string(runes[:max])
+	// A cut that lands exactly before a space keeps the whole last word,
+	// and a type prefix is not a word worth keeping on its own
+	prefix := len(conventionalPrefix.FindString(subject))
+	if runes[max] != ' ' {
+		if i := strings.LastIndexByte(cut, ' '); i >= prefix && i > 0 {
+			cut = cut[:i]
+		}
+	}
+	subject = strings.TrimRight(cut, " ,;:-")
+
+	if hasRest {
+		return subject + "\n" + rest
+	}
+	return subject
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "testing"
+
+func TestCollapseTypePrefixes(t *testing.T) {
+	for _, tt := range []struct {
+		message, want string
+	}{
+		{"feat: add x", "feat: add x"},
+		{"feat: feat: add x", "feat: add x"},
+		{"feat: feat(cmd): add x", "feat(cmd): add x"},
+		{"FEAT: fix(x): handle y", "fix(x): handle y"},
+		{"Feat: add x", "feat: add x"},
+		{"feat:add x", "feat: add x"},
+		{"feature: add x", "feat: add x"},
+		{"Bugfix(api)!: drop v1", "fix(api)!: drop v1"},
+		{"fix: chore:  tidy up\n\nBody: kept as is.", "chore: tidy up\n\nBody: kept as is."},
+		// The first word is not a type, so nothing is a prefix
+		{"Update: readme", "Update: readme"},
+		{"wip: feat: add x", "wip: feat: add x"},
+		{"add x", "add x"},
+		{"feat add x", "feat add x"},
+		{"", ""},
+	} {
+		if got := collapseTypePrefixes(tt.message); got != tt.want {
+			t.Errorf("collapseTypePrefixes(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-ai/ai"
+	"github.com/yourorg/arc-ai/internal/fileutil"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request duration
+// histogram.
+var latencyBuckets = []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// metrics counts the AI requests of one run for --metrics-file.
+type metrics struct {
+	path string
+	log  *slog.Logger
+
+	mu       sync.Mutex
+	requests map[string]int
+	failures map[string]int
+	latency  map[string]*histogram
+}
+
+// histogram is a cumulative Prometheus histogram over latencyBuckets.
+type histogram struct {
+	counts []int
+	sum    float64
+	count  int
+}
+
+// newMetrics returns metrics written to path, or nil if path is empty.
+func newMetrics(path string, log *slog.Logger) *metrics {
+	if path == "" {
+		return nil
+	}
+	return &metrics{
+		path:     path,
+		log:      log,
+		requests: map[string]int{},
+		failures: map[string]int{},
+		latency:  map[string]*histogram{},
+	}
+}
+
+// middleware is an ai.Middleware that records each request and rewrites
+// the metrics file, so the file is complete even if the command fails.
+func (m *metrics) middleware() ai.Middleware {
+	return func(next ai.Handler) ai.Handler {
+		return func(ctx context.Context, req ai.Request) (ai.Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			m.record(requestProvider(resp, err), time.Since(start), err != nil)
+			if werr := m.write(); werr != nil {
+				// Metrics are a side channel; losing them should not fail the command
+				m.log.Warn("metrics not written", "error", werr)
+			}
+			return resp, err
+		}
+	}
+}
+
+// requestProvider names the provider that answered or failed a request,
+// or "none" if no single provider did.
+func requestProvider(resp ai.Response, err error) string {
+	if err == nil {
+		return resp.Provider
+	}
+	var e *ai.Error
+	if errors.As(err, &e) && e.Provider != "" {
+		return e.Provider
+	}
+	return "none"
+}
+
+func (m *metrics) record(provider string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[provider]++
+	if failed {
+		m.failures[provider]++
+	}
+
+	h := m.latency[provider]
+	if h == nil {
+		h = &histogram{counts: make([]int, len(latencyBuckets))}
+		m.latency[provider] = h
+	}
+	seconds := d.Seconds()
+	for i, le := range latencyBuckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// write replaces the metrics file with the counts so far.
+func (m *metrics) write() error {
+	m.mu.Lock()
+	text := m.format()
+	m.mu.Unlock()
+
+	if err := fileutil.WriteAtomic(m.path, []byte(text)); err != nil {
+		return fmt.Errorf("write metrics: %w", err)
+	}
+	return nil
+}
+
+// format renders the metrics in the Prometheus text exposition format.
+func (m *metrics) format() string {
+	var b strings.Builder
+
+	b.WriteString("# HELP arc_ai_requests_total AI requests sent, by provider.\n")
+	b.WriteString("# TYPE arc_ai_requests_total counter\n")
+	for _, p := range sortedKeys(m.requests) {
+		fmt.Fprintf(&b, "arc_ai_requests_total{provider=%q} %d\n", p, m.requests[p])
+	}
+
+	b.WriteString("# HELP arc_ai_request_failures_total AI requests that failed, by provider.\n")
+	b.WriteString("# TYPE arc_ai_request_failures_total counter\n")
+	for _, p := range sortedKeys(m.requests) {
+		fmt.Fprintf(&b, "arc_ai_request_failures_total{provider=%q} %d\n", p, m.failures[p])
+	}
+
+	b.WriteString("# HELP arc_ai_request_duration_seconds Duration of AI requests, by provider.\n")
+	b.WriteString("# TYPE arc_ai_request_duration_seconds histogram\n")
+	for _, p := range sortedKeys(m.requests) {
+		h := m.latency[p]
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(&b, "arc_ai_request_duration_seconds_bucket{provider=%q,le=%q} %d\n",
+				p, strconv.FormatFloat(le, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(&b, "arc_ai_request_duration_seconds_bucket{provider=%q,le=\"+Inf\"} %d\n", p, h.count)
+		fmt.Fprintf(&b, "arc_ai_request_duration_seconds_sum{provider=%q} %s\n", p, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "arc_ai_request_duration_seconds_count{provider=%q} %d\n", p, h.count)
+	}
+
+	return b.String()
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
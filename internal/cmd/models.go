@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/ai"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// listModelsTimeout bounds each provider's model listing.
+const listModelsTimeout = 10 * time.Second
+
+func newModelsCmd() *cobra.Command {
+	var name string
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "List the models available from AI providers",
+		Long: `List the models that can be passed to --model.
+
+The Anthropic, OpenAI, Gemini, and Ollama providers are queried for their models;
+the claude CLI has a fixed list of aliases. Without --provider, every
+available provider is listed and unavailable ones are skipped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			log := newLogger(cmd)
+			var models []ai.Model
+			if name != "" {
+				provider, err := ai.Select(name, nil)
+				if err != nil {
+					return fromAIError(err)
+				}
+				models, err = listModels(ctx, provider)
+				if errors.Is(err, ai.ErrNoModelList) {
+					return fmt.Errorf("provider %s cannot list its models", provider)
+				}
+				if err != nil {
+					return err
+				}
+			} else {
+				for _, provider := range ai.Providers() {
+					if ai.Available(provider) != nil {
+						continue
+					}
+					list, err := listModels(ctx, provider)
+					if errors.Is(err, ai.ErrNoModelList) {
+						continue
+					}
+					if err != nil {
+						log.Warn("models not listed", "provider", provider, "error", err)
+						continue
+					}
+					models = append(models, list...)
+				}
+			}
+
+			if out.Is(output.OutputJSON) {
+				if models == nil {
+					models = []ai.Model{}
+				}
+				return output.JSON(models)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "PROVIDER\tMODEL\tDESCRIPTION")
+			for _, m := range models {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", m.Provider, m.ID, m.Description)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "provider", "", "Only list models from this provider")
+	_ = cmd.RegisterFlagCompletionFunc("provider", completeProviders)
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+func listModels(ctx context.Context, provider string) ([]ai.Model, error) {
+	ctx, cancel := context.WithTimeout(ctx, listModelsTimeout)
+	defer cancel()
+	return ai.Models(ctx, provider)
+}
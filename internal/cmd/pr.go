@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+func newPRCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var base string
+	var maxTokens int
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Draft a pull request description",
+		Long: `Draft a pull request title and description for the current branch.
+
+The branch is compared against --base (default main). Use --output json
+to get title and body fields, e.g. for 'gh pr create'.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if err := requireRepo(ctx); err != nil {
+				return err
+			}
+
+			commits, err := commitLog(ctx, base+"..HEAD")
+			if err != nil {
+				return err
+			}
+			if len(commits) == 0 {
+				return &Error{Kind: KindNoChanges, Msg: fmt.Sprintf("no commits between %s and HEAD", base)}
+			}
+
+			// Three dots: changes on this branch since it diverged from base
+			diff, err := git(ctx, "diff", base+"...HEAD")
+			if err != nil {
+				return err
+			}
+
+			// Split the budget between the commit list and the diff
+			log := truncateCommits(commits, maxTokens/4)
+			diff = truncateDiff(diff, maxTokens-estimateTokens(log))
+
+			prompt := fmt.Sprintf(`Write a pull request title and description for the following changes.
+The first line must be the title: a concise summary under 72 characters,
+without a leading "#" or "Title:". After a blank line, write the body in
+markdown with a "## Summary" section of bullet points describing what
+changed and why, and a "## Test plan" section describing how to verify it.
+Respond with ONLY the title and body, no explanations.
+
+Commits:
+%s
+Diff:
+%s`, log, diff)
+
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+				return err
+			}
+
+			response, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			title, body := splitTitle(response)
+
+			if out.Is(output.OutputJSON) {
+				return output.JSON(map[string]string{
+					"title": title,
+					"body":  body,
+				})
+			}
+
+			fmt.Printf("%s\n\n%s\n", title, body)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringVar(&base, "base", "main", "Base branch to compare against")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the changes sent to the AI")
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+// splitTitle separates the first line of a response from the rest,
+// dropping markdown heading or "Title:" prefixes the model may add.
+func splitTitle(response string) (title, body string) {
+	response = strings.TrimSpace(response)
+	title, body, _ = strings.Cut(response, "\n")
+
+	title = strings.TrimSpace(strings.TrimLeft(title, "#"))
+	title = strings.TrimSpace(strings.TrimPrefix(title, "Title:"))
+	title = strings.Trim(title, "*`")
+
+	return title, strings.TrimSpace(body)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// defaultCommitPrompt is the commit prompt template used unless
+// --prompt-template or prompt-template in the config file names another.
+//
+//go:embed prompts/commit.tmpl
+var defaultCommitPrompt string
+
+// commitPromptData is the data a commit prompt template is executed with.
+type commitPromptData struct {
+	// Diff is the change to describe, after --exclude, --anonymize, and
+	// truncation.
+	Diff string
+	// Summarized is set when Diff holds summaries of the parts of a diff
+	// too large to send, from --summarize-large.
+	Summarized bool
+	// RecentCommits are recent commit subjects, as style examples.
+	RecentCommits []string
+	// Scope is the --scope value with the conventional style, and
+	// ScopeHint the scope suggested by the changed paths otherwise.
+	Scope     string
+	ScopeHint string
+	// Lang is the --lang value, or "" for English.
+	Lang string
+	// Style is the --style name and StyleRules its instructions.
+	Style      string
+	StyleRules string
+	// SubjectMax is the longest subject line allowed.
+	SubjectMax int
+	// Template is the repository's commit message template, if any.
+	Template string
+	// Untracked holds the contents of untracked files, as context, with
+	// --include-untracked.
+	Untracked string
+	// Candidates is the number of messages to generate, separated by lines
+	// containing only Delimiter.
+	Candidates int
+	Delimiter  string
+}
+
+// promptDiffMarker stands in for the diff when a template is checked.
+const promptDiffMarker = "<<arc-ai diff>>"
+
+// parsePromptTemplate parses a commit prompt template and checks it by
+// executing it with sample data, so that unknown variables and a template
+// that leaves out the diff are reported before anything is sent. name is
+// used in error messages.
+func parsePromptTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("prompt template: %w", err)
+	}
+
+	// Every field is set so that conditional sections are checked too
+	var b strings.Builder
+	err = tmpl.Execute(&b, commitPromptData{
+		Diff:          promptDiffMarker,
+		Summarized:    true,
+		RecentCommits: []string{"feat: example"},
+		Scope:         "scope",
+		ScopeHint:     "scope",
+		Lang:          "English",
+		Style:         styleConventional,
+		StyleRules:    "rules",
+		SubjectMax:    defaultSubjectMax,
+		Template:      "template",
+		Untracked:     "untracked",
+		Candidates:    2,
+		Delimiter:     candidateDelimiter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prompt template: %w (variables: .Diff, .Summarized, .RecentCommits, .Scope, .ScopeHint, .Lang, .Style, .StyleRules, .SubjectMax, .Template, .Untracked, .Candidates, .Delimiter)", err)
+	}
+	if !strings.Contains(b.String(), promptDiffMarker) {
+		return nil, fmt.Errorf("prompt template %s does not include the diff ({{.Diff}})", name)
+	}
+	return tmpl, nil
+}
+
+// readPromptTemplate returns the text of the prompt template at path, or
+// of the default template if path is empty.
+func readPromptTemplate(path string) (string, error) {
+	if path == "" {
+		return defaultCommitPrompt, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("prompt template: %w", err)
+	}
+	return string(data), nil
+}
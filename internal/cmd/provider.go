@@ -0,0 +1,353 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ai/ai"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// providerAuto is the --provider value that picks the first available
+// provider.
+const providerAuto = ai.Auto
+
+// configureProviders registers the configured command providers and sets
+// up the client for provider APIs from --ca-cert and
+// --insecure-skip-verify, or else the config, and the configured base URLs.
+func configureProviders(cmd *cobra.Command) error {
+	var opts ai.HTTPOptions
+	// Commands that use the config report an invalid one themselves
+	if cfg, err := LoadConfig(); err == nil {
+		if err := registerCommandProviders(cfg); err != nil {
+			return err
+		}
+		opts = ai.HTTPOptions{CAFile: cfg.CACert, InsecureSkipVerify: cfg.InsecureSkipVerify}
+		for name, base := range cfg.BaseURLs {
+			if err := ai.SetBaseURL(name, base); err != nil {
+				return err
+			}
+		}
+	}
+	if cmd.Flags().Changed("ca-cert") {
+		opts.CAFile, _ = cmd.Flags().GetString("ca-cert")
+	}
+	if cmd.Flags().Changed("insecure-skip-verify") {
+		opts.InsecureSkipVerify, _ = cmd.Flags().GetBool("insecure-skip-verify")
+	}
+
+	client, err := ai.NewHTTPClient(opts)
+	if err != nil {
+		return err
+	}
+	if opts.InsecureSkipVerify {
+		newLogger(cmd).Debug("TLS certificate verification is disabled for provider APIs")
+	}
+	ai.SetHTTPClient(client)
+	return nil
+}
+
+// registerCommandProviders makes the command providers in cfg known to the
+// ai package, in order of name for auto-detection.
+func registerCommandProviders(cfg *Config) error {
+	names := make([]string, 0, len(cfg.CommandProviders))
+	for name := range cfg.CommandProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ai.RegisterCommand(name, cfg.CommandProviders[name].aiProvider()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// aiRequest is an ai.Request with the command's logger.
+type aiRequest struct {
+	ai.Request
+	// Log receives diagnostics; nil discards them.
+	Log *slog.Logger
+	// Metrics records the request for --metrics-file; nil records nothing.
+	Metrics *metrics
+	// Audit records the request for --audit-log; nil records nothing.
+	Audit *auditLog
+	// Stats totals the request for --stats; nil records nothing.
+	Stats *requestStats
+	// Progress is shown with a spinner on a terminal stderr while waiting
+	// for the response; "" shows nothing.
+	Progress string
+}
+
+func providerNames() []string {
+	return append([]string{providerAuto}, ai.Providers()...)
+}
+
+func validProvider(name string) bool {
+	for _, n := range providerNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// aiOptions holds the flags shared by commands that call askAI.
+type aiOptions struct {
+	model string
+	// models are the configured default models by provider name.
+	models map[string]string
+	// aliases map short model names to model IDs (see ai.ResolveModel).
+	aliases  map[string]string
+	provider string
+	order    []string
+	race     bool
+	retries  int
+	timeout  time.Duration
+	log      *slog.Logger
+	metrics  *metrics
+	audit    *auditLog
+	// localOnly refuses hosted providers.
+	localOnly bool
+	// largerModels map models to ones with larger context windows.
+	largerModels map[string]string
+	// stats totals the requests for --stats; nil if it is not set.
+	stats *requestStats
+	// modelFlags are the values of every --model given, in order; the
+	// last is model.
+	modelFlags []string
+	// baseURL replaces the endpoint of the provider's API.
+	baseURL string
+	// maxOutputTokens caps the length of responses; 0 leaves it to the
+	// provider.
+	maxOutputTokens int
+	// temperature and topP are sent only when set, by the flags or, for
+	// temperature, by the command's default.
+	temperature, topP       float64
+	temperatureSet, topPSet bool
+	// temperatureDefault is set when temperature is the command's default.
+	temperatureDefault bool
+
+	// yes skips the confirmation of large requests.
+	yes bool
+	// estimateOnly prints the estimated request size instead of sending it.
+	estimateOnly bool
+	// confirmTokens is the estimated size above which requests need
+	// confirmation; 0 never asks.
+	confirmTokens int
+	// rates are the configured input prices per million tokens by model.
+	rates map[string]float64
+	// progress is the spinner message while waiting for a response; ""
+	// disables the spinner.
+	progress string
+}
+
+// resolve fills in options that were not set on the command line from the
+// configured defaults, and picks up the global flags set on the root command.
+func (o *aiOptions) resolve(cmd *cobra.Command, cfg *Config) {
+	if !cmd.Flags().Changed("model") {
+		o.model = cfg.Model
+	}
+	o.models = cfg.Models
+	o.aliases = cfg.ModelAliases
+	o.largerModels = cfg.LargerModels
+	if !cmd.Flags().Changed("provider") {
+		o.provider = cfg.Provider
+	}
+	if !cmd.Flags().Changed("provider-order") {
+		o.order = cfg.Providers
+	}
+	if d, err := cmd.Flags().GetDuration("timeout"); err == nil {
+		o.timeout = d
+	}
+	if cmd.Flags().Changed("temperature") {
+		o.temperatureSet, o.temperatureDefault = true, false
+	}
+	o.topPSet = cmd.Flags().Changed("top-p")
+	o.localOnly = cfg.LocalOnly
+	if v, err := cmd.Flags().GetBool("local-only"); err == nil && v {
+		o.localOnly = true
+	}
+	o.log = newLogger(cmd)
+	if path, err := cmd.Flags().GetString("metrics-file"); err == nil {
+		o.metrics = newMetrics(path, o.log)
+	}
+	audit, full := cfg.AuditLog, cfg.AuditFull
+	if f := cmd.Flags().Lookup("audit-log"); f != nil && f.Changed {
+		audit = f.Value.String()
+	}
+	if f := cmd.Flags().Lookup("audit-full"); f != nil && f.Changed {
+		full = f.Value.String() == "true"
+	}
+	o.audit = newAuditLog(audit, cmd.Name(), full)
+	o.confirmTokens = cfg.ConfirmTokens
+	o.rates = cfg.Rates
+
+	// A spinner would be noise around machine-readable output, and would
+	// interleave with the log at info level or below
+	o.progress = ""
+	if f := cmd.Flags().Lookup("output"); isTerminal(os.Stderr) && !o.log.Enabled(context.Background(), slog.LevelInfo) &&
+		(f == nil || f.Value.String() == string(output.OutputTable)) {
+		o.progress = "Waiting for the AI"
+	}
+}
+
+// request builds an aiRequest for prompt using the flag values.
+func (o *aiOptions) request(prompt string) aiRequest {
+	req := aiRequest{
+		Request: ai.Request{
+			Prompt:          prompt,
+			Model:           o.model,
+			Models:          o.models,
+			Aliases:         o.aliases,
+			LargerModels:    o.largerModels,
+			Provider:        o.provider,
+			BaseURL:         o.baseURL,
+			MaxOutputTokens: o.maxOutputTokens,
+			Order:           o.order,
+			LocalOnly:       o.localOnly,
+			Race:            o.race,
+			Retries:         o.retries,
+			Timeout:         o.timeout,
+		},
+		Log:      o.log,
+		Metrics:  o.metrics,
+		Audit:    o.audit,
+		Stats:    o.stats,
+		Progress: o.progress,
+	}
+	if o.temperatureSet {
+		t := o.temperature
+		req.Temperature = &t
+	}
+	if o.topPSet {
+		p := o.topP
+		req.TopP = &p
+	}
+	return req
+}
+
+func (o *aiOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(modelFlag{o}, "model", "AI model to use")
+	cmd.Flags().StringVar(&o.provider, "provider", providerAuto,
+		"AI provider ("+strings.Join(providerNames(), "|")+")")
+	cmd.Flags().StringVar(&o.baseURL, "base-url", "", "API endpoint to use instead of the provider's, e.g. a gateway (anthropic, openai, and gemini only)")
+	cmd.Flags().StringSliceVar(&o.order, "provider-order", nil,
+		"Providers to try, in order, with the auto provider (default: "+strings.Join(ai.Providers(), ",")+")")
+	cmd.Flags().BoolVar(&o.race, "race", false, "Ask every available provider at once and use the first response (auto provider only)")
+	cmd.Flags().IntVar(&o.maxOutputTokens, "max-output-tokens", 0, "Longest response, in tokens (advisory for CLI providers; 0 for the provider's default)")
+	cmd.Flags().Float64Var(&o.temperature, "temperature", 0, "Sampling temperature from 0 to 2; higher gives more varied responses (ignored by CLI providers)")
+	cmd.Flags().Float64Var(&o.topP, "top-p", 0, "Nucleus sampling probability, above 0 and at most 1 (ignored by CLI providers)")
+	cmd.Flags().IntVar(&o.retries, "retries", ai.DefaultRetries, "Retries for transient provider failures")
+	cmd.Flags().BoolVarP(&o.yes, "yes", "y", false, "Proceed without asking for confirmation, e.g. of large requests")
+	cmd.Flags().BoolVar(&o.estimateOnly, "estimate-only", false, "Print the estimated request size and cost without sending it")
+	_ = cmd.RegisterFlagCompletionFunc("model", completeModels)
+	_ = cmd.RegisterFlagCompletionFunc("provider", completeProviders)
+	_ = cmd.RegisterFlagCompletionFunc("provider-order", completeProviders)
+}
+
+// modelFlag is the value of --model, which keeps the last model given as
+// the model and records them all for commands that can use several.
+type modelFlag struct{ o *aiOptions }
+
+func (f modelFlag) String() string { return f.o.model }
+func (f modelFlag) Type() string   { return "string" }
+
+func (f modelFlag) Set(v string) error {
+	f.o.model = v
+	f.o.modelFlags = append(f.o.modelFlags, v)
+	return nil
+}
+
+// setDefaultTemperature makes t the temperature of the command's requests
+// when --temperature is not given. It must follow addFlags.
+func (o *aiOptions) setDefaultTemperature(cmd *cobra.Command, t float64) {
+	o.temperature, o.temperatureSet, o.temperatureDefault = t, true, true
+	cmd.Flags().Lookup("temperature").DefValue = strconv.FormatFloat(t, 'g', -1, 64)
+}
+
+// varyTemperature leaves the temperature of later requests to the
+// provider, unless --temperature set it, so that asking again can give a
+// different response.
+func (o *aiOptions) varyTemperature() {
+	if o.temperatureDefault {
+		o.temperatureSet, o.temperatureDefault = false, false
+	}
+}
+
+// askAI sends a prompt to the AI through the ai package and returns the
+// response text. Responses are cached in the arc-ai cache directory.
+func askAI(ctx context.Context, req aiRequest) (string, error) {
+	resp, err := askAIResponse(ctx, req)
+	return resp.Text, err
+}
+
+// askAIResponse is askAI returning the whole response, including the
+// provider and model that answered.
+func askAIResponse(ctx context.Context, req aiRequest) (ai.Response, error) {
+	if req.MaxOutputTokens < 0 {
+		return ai.Response{}, fmt.Errorf("--max-output-tokens must be positive")
+	}
+	if t := req.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return ai.Response{}, fmt.Errorf("--temperature must be from 0 to 2")
+	}
+	if p := req.TopP; p != nil && (*p <= 0 || *p > 1) {
+		return ai.Response{}, fmt.Errorf("--top-p must be greater than 0 and at most 1")
+	}
+	if req.BaseURL != "" {
+		// Each API has its own request format, so the URL is for one
+		if req.Provider == "" || req.Provider == providerAuto {
+			return ai.Response{}, fmt.Errorf("--base-url requires --provider %s, %s, or %s", ai.Anthropic, ai.OpenAI, ai.Gemini)
+		}
+		if err := ai.CheckBaseURL(req.Provider, req.BaseURL); err != nil {
+			return ai.Response{}, err
+		}
+	}
+
+	inflight.Add(1)
+	defer inflight.Add(-1)
+
+	client := ai.Client{Log: req.Log, Middleware: []ai.Middleware{logUsage(req.Log)}}
+	if req.Metrics != nil {
+		client.Middleware = append(client.Middleware, req.Metrics.middleware())
+	}
+	if req.Audit != nil {
+		client.Middleware = append(client.Middleware, req.Audit.middleware())
+	}
+	if req.Stats != nil {
+		client.Middleware = append(client.Middleware, req.Stats.middleware())
+	}
+	if req.CacheTTL > 0 {
+		dir, err := cacheDir()
+		if err != nil {
+			return ai.Response{}, err
+		}
+		client.CacheDir = dir
+	}
+
+	// A streamed response is its own progress indicator
+	if req.Progress != "" && req.Stream == nil {
+		s := startSpinner(ctx, os.Stderr, req.Progress)
+		defer s.Stop()
+	}
+
+	resp, err := client.Ask(ctx, req.Request)
+	if errors.Is(err, ai.ErrContextLength) {
+		return ai.Response{}, fmt.Errorf("%w; lower --max-tokens, or name a model with a larger context window for it under larger-models in the config file", fromAIError(err))
+	}
+	if err != nil {
+		return ai.Response{}, fromAIError(err)
+	}
+	return resp, nil
+}
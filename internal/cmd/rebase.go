@@ -0,0 +1,237 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// rangeDiffPair matches a line of git range-diff that pairs a commit
+// before with one after, capturing how they compare: = for the same
+// change and message, ! for a changed one, < for dropped, > for new.
+var rangeDiffPair = regexp.MustCompile(`^\s*(?:\d+|-+):\s+(?:[0-9a-f]+|-+)\s+([=!<>])\s`)
+
+// rebaseResult is the --output json form of rebase-summary.
+type rebaseResult struct {
+	Upstream string `json:"upstream"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+	Summary  string `json:"summary"`
+}
+
+func newRebaseSummaryCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var before string
+	var maxTokens int
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "rebase-summary <upstream>",
+		Short: "Summarize what a rebase changed",
+		Long: `Describe what the last rebase of the current branch changed: which
+commits were reordered, squashed, split, dropped, reworded, or edited, and
+the net change to the code.
+
+The commits on the branch since upstream are compared with those it had
+before the rebase, found in the branch's reflog, or given by --before. If
+the rebase changed nothing, the command says so and exits 5 without
+asking the AI.
+
+Use --output json to get the summary with the upstream and the commits
+before and after.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if err := requireRepo(ctx); err != nil {
+				return err
+			}
+
+			upstream := args[0]
+			if before == "" {
+				before, err = preRebaseHead(ctx)
+				if err != nil {
+					return err
+				}
+			}
+			beforeHash, err := resolveCommit(ctx, before)
+			if err != nil {
+				return err
+			}
+			afterHash, err := resolveCommit(ctx, "HEAD")
+			if err != nil {
+				return err
+			}
+			if _, err := resolveCommit(ctx, upstream); err != nil {
+				return err
+			}
+			if beforeHash == afterHash {
+				return &Error{Kind: KindNoChanges, Msg: fmt.Sprintf("nothing changed: HEAD is still %s", short(beforeHash))}
+			}
+
+			rangeDiff, err := git(ctx, "range-diff", "--no-color", upstream, beforeHash, afterHash)
+			if err != nil {
+				return err
+			}
+
+			// With the same base, the diff between the tips is the net
+			// change; after a move to a new base it would mostly be upstream's
+			oldBase, err := git(ctx, "merge-base", upstream, beforeHash)
+			if err != nil {
+				return err
+			}
+			newBase, err := git(ctx, "merge-base", upstream, afterHash)
+			if err != nil {
+				return err
+			}
+			var net string
+			if oldBase == newBase {
+				net, err = git(ctx, "diff", "--no-color", beforeHash, afterHash)
+				if err != nil {
+					return err
+				}
+				if net == "" && unchangedCommits(rangeDiff) {
+					return &Error{Kind: KindNoChanges, Msg: "nothing changed: the commits have the same changes and messages as before"}
+				}
+			}
+
+			// Split the budget between the commit comparison and the net diff
+			rangeDiff = truncateText(rangeDiff, maxTokens*3/4)
+			net = truncateDiff(net, maxTokens-estimateTokens(rangeDiff))
+
+			prompt := rebasePrompt(rangeDiff, net, oldBase != newBase)
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+				return err
+			}
+
+			response, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+			summary := strings.TrimSpace(response)
+
+			if out.Is(output.OutputJSON) {
+				return output.JSON(rebaseResult{
+					Upstream: upstream,
+					Before:   beforeHash,
+					After:    afterHash,
+					Summary:  summary,
+				})
+			}
+
+			fmt.Println(summary)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringVar(&before, "before", "", "The branch as it was before the rebase (default: found in the reflog)")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the changes sent to the AI")
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+// rebasePrompt asks for a summary of a rebase from the range-diff of the
+// commits before and after it and, if the base did not move, the net diff.
+func rebasePrompt(rangeDiff, net string, moved bool) string {
+	var b strings.Builder
+	b.WriteString(`Summarize what a rebase changed on a git branch, for someone reviewing
+the rewritten branch. Say which commits were reordered, squashed or fixed
+up, split, dropped, added, reworded, or edited, and describe the net
+change to the code in a sentence or two. Leave out commits that are
+unchanged. Write a few short markdown bullet points.
+
+The comparison below is git range-diff output: each line pairs a commit
+before the rebase with one after, marked = if unchanged, ! if changed
+(followed by the differences between the two patches), < if dropped, and
+> if new.
+`)
+	if moved {
+		b.WriteString("\nThe branch was also moved onto a newer base.\n")
+	}
+	fmt.Fprintf(&b, "\nCommits before and after:\n%s\n", rangeDiff)
+	if net != "" {
+		fmt.Fprintf(&b, "\nNet diff of the branch, before to after:\n%s\n", net)
+	}
+	b.WriteString("\nRespond with ONLY the summary.")
+	return b.String()
+}
+
+// preRebaseHead returns the commit the current branch pointed to before
+// its most recent rebase, from the branch's reflog.
+func preRebaseHead(ctx context.Context) (string, error) {
+	branch, err := currentBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if branch == "HEAD" {
+		return "", fmt.Errorf("HEAD is detached (is a rebase in progress?); pass --before")
+	}
+
+	out, err := git(ctx, "log", "--walk-reflogs", "--format=%H%x1f%gs", "refs/heads/"+branch)
+	if err != nil {
+		return "", err
+	}
+	entries := strings.Split(out, "\n")
+	for i, e := range entries {
+		_, subject, _ := strings.Cut(e, "\x1f")
+		// "rebase (finish): ..." or, from older gits, "rebase -i (finish): ..."
+		if strings.HasPrefix(subject, "rebase") && strings.Contains(subject, "(finish)") && i+1 < len(entries) {
+			hash, _, _ := strings.Cut(entries[i+1], "\x1f")
+			return hash, nil
+		}
+	}
+	return "", fmt.Errorf("no rebase of %s found in its reflog; pass --before", branch)
+}
+
+// resolveCommit returns the full hash of the commit rev names.
+func resolveCommit(ctx context.Context, rev string) (string, error) {
+	hash, err := git(ctx, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision %s", rev)
+	}
+	return hash, nil
+}
+
+// unchangedCommits reports whether range-diff output pairs every commit
+// with an identical one.
+func unchangedCommits(rangeDiff string) bool {
+	for _, line := range strings.Split(rangeDiff, "\n") {
+		if m := rangeDiffPair.FindStringSubmatch(line); m != nil && m[1] != "=" {
+			return false
+		}
+	}
+	return true
+}
+
+// short abbreviates a commit hash for messages.
+func short(hash string) string {
+	return hash[:min(len(hash), 12)]
+}
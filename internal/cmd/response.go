@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// decodeResponseJSON extracts the JSON value from an AI response into v.
+// Models often wrap JSON in prose or a code fence, so this decodes the
+// outermost object or array found in the text.
+func decodeResponseJSON(response string, v any) error {
+	text, err := responseJSON(response)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(text), v); err != nil {
+		return fmt.Errorf("parse AI response: %w", err)
+	}
+	return nil
+}
+
+// responseJSON returns the text of the outermost object or array in an AI
+// response, without checking that it is valid JSON.
+func responseJSON(response string) (string, error) {
+	start := strings.IndexAny(response, "[{")
+	if start < 0 {
+		return "", fmt.Errorf("AI response did not contain JSON")
+	}
+
+	closer := "}"
+	if response[start] == '[' {
+		closer = "]"
+	}
+	end := strings.LastIndex(response, closer)
+	if end < start {
+		return "", fmt.Errorf("AI response did not contain JSON")
+	}
+	return response[start : end+1], nil
+}
+
+// stripCodeFence returns the contents of the first fenced code block in
+// response, or the whole response if it has none.
+func stripCodeFence(response string) string {
+	start := strings.Index(response, "```")
+	if start < 0 {
+		return strings.TrimSpace(response)
+	}
+
+	// The fence may be longer than three backticks and name a language
+	fence := "```"
+	for start+len(fence) < len(response) && response[start+len(fence)] == '`' {
+		fence += "`"
+	}
+	body := response[start+len(fence):]
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		body = body[nl+1:]
+	}
+	if end := strings.Index(body, fence); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
+
+// unwrapCodeFence removes a code fence, with an optional language tag, that
+// surrounds the whole of s. Fenced blocks within the text and inline
+// backticks are left alone.
+func unwrapCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	fence := s[:len(s)-len(strings.TrimLeft(s, "`"))]
+	if len(fence) < 3 {
+		return s
+	}
+
+	open, body, ok := strings.Cut(s, "\n")
+	if !ok || strings.Contains(open[len(fence):], "`") {
+		return s
+	}
+	// The closing fence must be exactly as long and on a line of its own
+	body, ok = strings.CutSuffix("\n"+body, "\n"+fence)
+	if !ok {
+		return s
+	}
+	return strings.TrimSpace(body)
+}
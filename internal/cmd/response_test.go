@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import "testing"
+
+func TestUnwrapCodeFence(t *testing.T) {
+	for _, tt := range []struct {
+		name, in, want string
+	}{
+		{"unfenced", "feat: add x\n\nBody.", "feat: add x\n\nBody."},
+		{"fenced", "```\nfeat: add x\n```", "feat: add x"},
+		{"language tag", "```text\nfeat: add x\n\nBody.\n```", "feat: add x\n\nBody."},
+		{"surrounding space", "\n  ```git\nfix: y\n```  \n", "fix: y"},
+		{"longer fence", "````\nfeat: add x\n````", "feat: add x"},
+		{"inline backticks", "fix: handle `nil` in `Parse`", "fix: handle `nil` in `Parse`"},
+		{"inline backticks first", "`go vet` is clean now", "`go vet` is clean now"},
+		{"fenced inline backticks", "```\nfix: handle `nil`\n```", "fix: handle `nil`"},
+		{
+			"fenced block within the text",
+			"docs: add example\n\n```go\nx := 1\n```",
+			"docs: add example\n\n```go\nx := 1\n```",
+		},
+		{
+			"inner fence kept",
+			"````\ndocs: add example\n\n```go\nx := 1\n```\n````",
+			"docs: add example\n\n```go\nx := 1\n```",
+		},
+		{"no closing fence", "```\nfeat: add x", "```\nfeat: add x"},
+		{"closing fence of another length", "````\nfeat: add x\n```", "````\nfeat: add x\n```"},
+		{"closing fence not on its own line", "```\nfeat: add x```", "```\nfeat: add x```"},
+		{"one line", "```feat: add x```", "```feat: add x```"},
+		{"empty", "", ""},
+	} {
+		if got := unwrapCodeFence(tt.in); got != tt.want {
+			t.Errorf("%s: unwrapCodeFence(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// finding is a single issue reported by the review command.
+type finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// reviewComment is a GitHub pull request review comment, attached to a
+// line by its position in the file's diff.
+type reviewComment struct {
+	Path     string `json:"path"`
+	Position int    `json:"position"`
+	Body     string `json:"body"`
+}
+
+// githubReview is the --inline --output json form of review: the body of a
+// request to GitHub's create-a-review endpoint.
+type githubReview struct {
+	Event    string          `json:"event"`
+	Comments []reviewComment `json:"comments"`
+}
+
+func newReviewCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var staged bool
+	var maxTokens int
+	var secrets secretOptions
+	var exclude []string
+	var recurseSubmodules bool
+	var inline bool
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "AI code review of changes",
+		Long: `Review changes for bugs, security issues, and style problems.
+
+By default the staged diff ('git diff --cached') is reviewed; use
+--staged=false to review the working-tree diff instead.
+
+The diff is scanned for likely secrets before it is sent, as for commit.
+--exclude leaves files matching a glob out of the review, and
+--recurse-submodules reviews the changes inside changed submodules too.
+
+With --inline, each finding is tied to a line of the diff and printed as
+file:line with that line beneath it; with --output json as well, the
+findings are GitHub review comments, with the path and diff position
+GitHub expects, ready to post as a review. Findings the AI ties to lines
+that are not in the diff are left out.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if err := requireRepo(ctx); err != nil {
+				return err
+			}
+
+			diff, err := gitDiff(ctx, staged, diffFormat{recurseSubmodules: recurseSubmodules}, nil, exclude)
+			if err != nil {
+				return err
+			}
+
+			if len(diff) == 0 {
+				if staged {
+					return ErrNoStagedChanges
+				}
+				return &Error{Kind: KindNoChanges, Msg: "no changes"}
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			if ok, err := secrets.check(reader, diff); !ok {
+				return err
+			}
+
+			if inline {
+				return reviewInline(ctx, &aiOpts, reader, diff, truncateDiff(diff, maxTokens), out.Is(output.OutputJSON))
+			}
+
+			diff = truncateDiff(diff, maxTokens)
+
+			if out.Is(output.OutputJSON) {
+				prompt := fmt.Sprintf(`Review the following diff for bugs, security issues, and style problems.
+Respond with ONLY a JSON array of findings, no explanations. Each finding is an object with:
+- "file": the file path
+- "line": the line number in the new file (0 if unknown)
+- "severity": one of "error", "warning", or "info"
+- "message": a concise description of the issue
+Respond with [] if there are no findings.
+
+Diff:
+%s`, diff)
+
+				if ok, err := aiOpts.preflight(reader, prompt); !ok {
+					return err
+				}
+
+				response, err := askAI(ctx, aiOpts.request(prompt))
+				if err != nil {
+					return err
+				}
+
+				findings := []finding{}
+				if err := decodeResponseJSON(response, &findings); err != nil {
+					return err
+				}
+
+				return output.JSON(map[string]any{
+					"findings": findings,
+				})
+			}
+
+			prompt := fmt.Sprintf(`Review the following diff. Focus on:
+- Bugs and logic errors
+- Security issues
+- Style and readability
+
+Reference files and lines where possible. Be concise and skip praise.
+
+Diff:
+%s`, diff)
+
+			if ok, err := aiOpts.preflight(reader, prompt); !ok {
+				return err
+			}
+
+			review, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(review)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the diff sent to the AI")
+	cmd.Flags().BoolVar(&staged, "staged", true, "Review staged changes (false reviews the working tree)")
+	secrets.addFlags(cmd)
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Glob of paths to leave out of the review (repeatable)")
+	cmd.Flags().BoolVar(&recurseSubmodules, "recurse-submodules", false, "Review the changes inside changed submodules too")
+	cmd.Flags().BoolVar(&inline, "inline", false, "Tie findings to lines of the diff (GitHub review comments with --output json)")
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
+
+// reviewInline asks for findings tied to lines of sent, the diff as it is
+// sent to the AI, and prints them with the lines they are about. Lines
+// and positions come from full, the diff before truncation, which keeps
+// the leading hunks of each file, so positions match the whole diff.
+func reviewInline(ctx context.Context, o *aiOptions, reader *bufio.Reader, full, sent string, github bool) error {
+	prompt := fmt.Sprintf(`Review the following diff for bugs, security issues, and style problems,
+and tie each finding to the line it is about. Each line of a hunk starts
+with its line number in the new file; removed lines have none. Only
+comment on numbered lines, and prefer added lines (marked +).
+
+Respond with ONLY a JSON array of findings, no explanations. Each finding is an object with:
+- "file": the file path, as in the diff header, without an a/ or b/ prefix
+- "line": the number of the line the finding is about
+- "severity": one of "error", "warning", or "info"
+- "message": a concise description of the issue
+Respond with [] if there are no findings.
+
+Diff:
+%s`, numberDiff(sent))
+
+	if ok, err := o.preflight(reader, prompt); !ok {
+		return err
+	}
+
+	response, err := askAI(ctx, o.request(prompt))
+	if err != nil {
+		return err
+	}
+
+	findings := []finding{}
+	if err := decodeResponseJSON(response, &findings); err != nil {
+		return err
+	}
+
+	files := diffLines(full)
+	type placed struct {
+		finding
+		diffLine
+	}
+	var kept []placed
+	for _, f := range findings {
+		lines, ok := files[f.File]
+		if !ok {
+			f.File = strings.TrimPrefix(f.File, "b/")
+			lines = files[f.File]
+		}
+		line, ok := lines[f.Line]
+		if !ok {
+			o.log.Warn("finding is not on a line of the diff; left out", "file", f.File, "line", f.Line, "message", f.Message)
+			continue
+		}
+		kept = append(kept, placed{f, line})
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].File != kept[j].File {
+			return kept[i].File < kept[j].File
+		}
+		return kept[i].Line < kept[j].Line
+	})
+
+	if github {
+		review := githubReview{Event: "COMMENT", Comments: []reviewComment{}}
+		for _, p := range kept {
+			review.Comments = append(review.Comments, reviewComment{
+				Path:     p.File,
+				Position: p.position,
+				Body:     fmt.Sprintf("**%s**: %s", p.Severity, p.Message),
+			})
+		}
+		return output.JSON(review)
+	}
+
+	if len(kept) == 0 {
+		fmt.Println("No findings.")
+		return nil
+	}
+	for i, p := range kept {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:%d: %s: %s\n", p.File, p.Line, p.Severity, p.Message)
+		fmt.Printf("%6d | %s\n", p.Line, p.text)
+	}
+	return nil
+}
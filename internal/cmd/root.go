@@ -4,17 +4,15 @@
 package cmd
 
 import (
-	"bufio"
-	"context"
-	"fmt"
-	"os"
-	"os/exec"
-	"strings"
+	"errors"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/yourorg/arc-sdk/output"
 )
 
+// defaultTimeout bounds each AI request unless --timeout says otherwise.
+const defaultTimeout = 60 * time.Second
+
 // NewRootCmd creates the root command for arc-ai.
 func NewRootCmd() *cobra.Command {
 	root := &cobra.Command{
@@ -22,209 +20,119 @@ func NewRootCmd() *cobra.Command {
 		Short: "AI-powered tools",
 		Long: `AI-powered development tools.
 
-Generate commit messages, analyze code, and more using AI models.`,
-	}
-
-	root.AddCommand(newCommitCmd())
-	root.AddCommand(newAskCmd())
-
-	return root
-}
-
-func newCommitCmd() *cobra.Command {
-	var model string
-	var dryRun bool
-
-	cmd := &cobra.Command{
-		Use:   "commit",
-		Short: "Generate AI commit message",
-		Long: `Generate a commit message based on staged changes.
-
-This command runs 'git diff --cached' and sends the diff to an AI model
-to generate a meaningful commit message.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx := cmd.Context()
-			if ctx == nil {
-				ctx = context.Background()
-			}
-
-			// Get staged diff
-			diffCmd := exec.CommandContext(ctx, "git", "diff", "--cached")
-			diffOutput, err := diffCmd.Output()
-			if err != nil {
-				return fmt.Errorf("git diff failed: %w", err)
-			}
-
-			if len(diffOutput) == 0 {
-				return fmt.Errorf("no staged changes")
-			}
-
-			diff := string(diffOutput)
-			if len(diff) > 10000 {
-				diff = diff[:10000] + "\n... (truncated)"
-			}
-
-			// Generate commit message using AI
-			prompt := fmt.Sprintf(`Generate a concise git commit message for the following diff.
-Use conventional commit format (feat:, fix:, docs:, refactor:, etc.).
-Keep the message under 72 characters for the subject line.
-Include a brief body if needed.
-
-Diff:
-%s
-
-Respond with ONLY the commit message, no explanations.`, diff)
-
-			fmt.Println("Generating commit message...")
-
-			message, err := askAI(ctx, prompt, model)
-			if err != nil {
-				return fmt.Errorf("AI request failed: %w", err)
-			}
-
-			message = strings.TrimSpace(message)
-
-			if dryRun {
-				fmt.Printf("\nSuggested commit message:\n%s\n", message)
-				return nil
-			}
-
-			// Confirm with user
-			fmt.Printf("\nSuggested commit message:\n%s\n\n", message)
-			fmt.Print("Use this message? [Y/n]: ")
+Generate commit messages, analyze code, and more using AI models.
 
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
-
-			if response != "" && response != "y" && response != "yes" {
-				fmt.Println("Commit cancelled.")
-				return nil
-			}
+API keys such as ANTHROPIC_API_KEY can be kept in a .env file in the
+working directory; other variables in it are loaded only when it is named
+with --env-file, which can also name another file. Variables already in
+the environment take precedence over the file.
 
-			// Create the commit
-			commitCmd := exec.CommandContext(ctx, "git", "commit", "-m", message)
-			commitCmd.Stdout = os.Stdout
-			commitCmd.Stderr = os.Stderr
+The Anthropic, OpenAI, Gemini, and Ollama APIs are reached through the
+proxies named by HTTP_PROXY, HTTPS_PROXY, and NO_PROXY. --ca-cert trusts
+the certificate authorities in a PEM file as well, for a proxy that
+intercepts TLS, and --insecure-skip-verify accepts any certificate.
 
-			if err := commitCmd.Run(); err != nil {
-				return fmt.Errorf("git commit failed: %w", err)
-			}
+Ctrl-C stops the command, killing provider CLIs and aborting requests.
 
-			return nil
-		},
-	}
-
-	cmd.Flags().StringVar(&model, "model", "", "AI model to use")
-	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show message without committing")
-
-	return cmd
-}
-
-func newAskCmd() *cobra.Command {
-	var model string
-	var out output.OutputOptions
-
-	cmd := &cobra.Command{
-		Use:   "ask <question>",
-		Short: "Ask AI a question",
-		Long: `Ask an AI model a question and get a response.
-
-The question can be provided as arguments or piped via stdin.`,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := out.Resolve(); err != nil {
-				return err
-			}
-
-			ctx := cmd.Context()
-			if ctx == nil {
-				ctx = context.Background()
-			}
-
-			var question string
-			if len(args) > 0 {
-				question = strings.Join(args, " ")
-			} else {
-				// Read from stdin
-				scanner := bufio.NewScanner(os.Stdin)
-				var lines []string
-				for scanner.Scan() {
-					lines = append(lines, scanner.Text())
+Exit codes: 0 success, 1 error, 2 invalid command line, 3 no usable
+provider, 4 provider failed, 5 nothing to do (such as no staged changes),
+6 not a git repository, 7 cancelled at a prompt, 130 interrupted (143 for
+SIGTERM).`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if level, _ := cmd.Flags().GetString("log-level"); level != "" {
+				if _, err := parseLogLevel(level); err != nil {
+					return err
 				}
-				question = strings.Join(lines, "\n")
 			}
-
-			if strings.TrimSpace(question) == "" {
-				return fmt.Errorf("no question provided")
+			if path, _ := cmd.Flags().GetString("env-file"); path != "" {
+				if err := loadEnvFile(path, cmd.Flags().Changed("env-file"), newLogger(cmd)); err != nil {
+					cmd.SilenceUsage = true
+					return err
+				}
 			}
-
-			response, err := askAI(ctx, question, model)
-			if err != nil {
+			if err := configureProviders(cmd); err != nil {
+				cmd.SilenceUsage = true
 				return err
 			}
-
-			if out.Is(output.OutputJSON) {
-				return output.JSON(map[string]string{
-					"question": question,
-					"response": response,
-				})
-			}
-
-			fmt.Println(response)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&model, "model", "", "AI model to use")
-	out.AddOutputFlags(cmd, output.OutputTable)
-
-	return cmd
-}
-
-// askAI sends a prompt to the AI and returns the response.
-// It tries multiple providers in order of preference.
-func askAI(ctx context.Context, prompt, model string) (string, error) {
-	// Try Claude CLI first
-	if _, err := exec.LookPath("claude"); err == nil {
-		return askClaude(ctx, prompt, model)
-	}
+	root.PersistentFlags().Duration("timeout", defaultTimeout, "Maximum duration of each AI request (0 for no limit)")
+	root.PersistentFlags().BoolP("verbose", "v", false, "Log provider, model, and timing details to stderr (same as --log-level debug)")
+	root.PersistentFlags().String("log-level", "warn", "Least severe messages to log to stderr: debug, info, warn, or error")
+	root.PersistentFlags().String("env-file", defaultEnvFile, "File of environment variables to load; by default only API keys are loaded from it (\"\" to skip)")
+	root.PersistentFlags().String("metrics-file", "", "Write Prometheus metrics about AI requests to this file")
+	root.PersistentFlags().String("audit-log", "", "Append a JSONL record of each AI request to this file")
+	root.PersistentFlags().Bool("audit-full", false, "Record full prompts in the audit log instead of their SHA-256 hashes")
+	root.PersistentFlags().Bool("local-only", false, "Refuse hosted providers; use only local ones such as Ollama")
+	root.PersistentFlags().String("ca-cert", "", "PEM file of extra certificate authorities to trust for provider APIs")
+	root.PersistentFlags().Bool("insecure-skip-verify", false, "Do not verify the TLS certificates of provider APIs")
 
-	// Try codex CLI
-	if _, err := exec.LookPath("codex"); err == nil {
-		return askCodex(ctx, prompt, model)
-	}
+	_ = root.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions(logLevels, cobra.ShellCompDirectiveNoFileComp))
 
-	return "", fmt.Errorf("no AI provider available (install claude or codex CLI)")
+	root.AddCommand(newCommitCmd())
+	root.AddCommand(newAskCmd())
+	root.AddCommand(newReviewCmd())
+	root.AddCommand(newChangelogCmd())
+	root.AddCommand(newPRCmd())
+	root.AddCommand(newRebaseSummaryCmd())
+	root.AddCommand(newBranchCmd())
+	root.AddCommand(newExplainCmd())
+	root.AddCommand(newSummaryCmd())
+	root.AddCommand(newTranslateCmd())
+	root.AddCommand(newTestCmd())
+	root.AddCommand(newDocstringCmd())
+	root.AddCommand(newHookCmd())
+	root.AddCommand(newDoctorCmd())
+	root.AddCommand(newConfigCmd())
+	root.AddCommand(newModelsCmd())
+	root.AddCommand(newHistoryCmd())
+	root.AddCommand(newCompletionCmd())
+
+	silenceRunUsage(root)
+	return root
 }
 
-func askClaude(ctx context.Context, prompt, model string) (string, error) {
-	args := []string{"--print"}
-	if model != "" {
-		args = append(args, "--model", model)
+// Execute runs arc-ai with the command-line arguments and returns the
+// process exit code: ExitUsage for an invalid command line, the signal's
+// code if the command was interrupted, or else the ExitCode of the error,
+// if any.
+func Execute() int {
+	ctx, caught, stop := handleInterrupts()
+	defer stop()
+
+	cmd, err := NewRootCmd().ExecuteContextC(ctx)
+	if sig := caught(); sig != nil {
+		return signalExitCode(sig)
 	}
-	args = append(args, prompt)
-
-	cmd := exec.CommandContext(ctx, "claude", args...)
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("claude failed: %w", err)
+	if err == nil {
+		return ExitOK
 	}
-
-	return strings.TrimSpace(string(output)), nil
+	// Usage is silenced once the command runs, so it is still shown only
+	// for mistakes in the command line
+	var e *Error
+	if !cmd.SilenceUsage && !errors.As(err, &e) {
+		return ExitUsage
+	}
+	return ExitCode(err)
 }
 
-func askCodex(ctx context.Context, prompt, model string) (string, error) {
-	args := []string{"ask"}
-	if model != "" {
-		args = append(args, "--model", model)
+// silenceRunUsage keeps cobra from printing usage for the errors that c
+// and its subcommands return once they run, which are not mistakes in the
+// command line, and from printing the error of an interrupted command.
+func silenceRunUsage(c *cobra.Command) {
+	if run := c.RunE; run != nil {
+		c.RunE = func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			err := run(cmd, args)
+			if err != nil && cmd.Context().Err() != nil {
+				cmd.SilenceErrors = true
+			}
+			return err
+		}
 	}
-	args = append(args, prompt)
-
-	cmd := exec.CommandContext(ctx, "codex", args...)
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("codex failed: %w", err)
+	for _, sub := range c.Commands() {
+		silenceRunUsage(sub)
 	}
-
-	return strings.TrimSpace(string(output)), nil
 }
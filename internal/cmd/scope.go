@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// conventionalPrefix matches the type, optional scope, and optional breaking
+// change marker of a conventional commit subject.
+var conventionalPrefix = regexp.MustCompile(`^([a-z]+)(\([^)]*\))?(!?): `)
+
+// detectScope derives a conventional commit scope from the paths changed in
+// diff: the last element of the directory they all share, so changes under
+// internal/cmd get "cmd". It returns "" when the changes span several
+// top-level directories or touch files at the repository root, rather than
+// guess.
+func detectScope(diff string) string {
+	var common []string
+	for i, f := range parseDiff(diff) {
+		if f.path == "" {
+			continue
+		}
+		dir := path.Dir(f.path)
+		if dir == "." {
+			return ""
+		}
+		parts := strings.Split(dir, "/")
+		if i == 0 {
+			common = parts
+			continue
+		}
+		n := 0
+		for n < len(common) && n < len(parts) && common[n] == parts[n] {
+			n++
+		}
+		common = common[:n]
+		if len(common) == 0 {
+			return ""
+		}
+	}
+	if len(common) == 0 {
+		return ""
+	}
+	return common[len(common)-1]
+}
+
+// applyScope sets the scope of a conventional commit message's subject,
+// replacing any scope the AI chose. Messages without a type prefix are
+// returned unchanged.
+func applyScope(message, scope string) string {
+	m := conventionalPrefix.FindStringSubmatchIndex(message)
+	if m == nil {
+		return message
+	}
+	kind := message[m[2]:m[3]]
+	breaking := message[m[6]:m[7]]
+	return kind + "(" + scope + ")" + breaking + ": " + message[m[1]:]
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// secretPatterns match well-known credential formats.
+var secretPatterns = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{"API key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}\b`)},
+}
+
+// tokenCandidate matches strings long enough to be a random token.
+var tokenCandidate = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+
+// minTokenEntropy is the Shannon entropy in bits per character above which
+// a token is treated as random. Hex digests top out at 4 and are ignored.
+const minTokenEntropy = 4.5
+
+// checksumFiles are lockfiles full of hashes that would otherwise trip the
+// entropy check.
+var checksumFiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"composer.lock":     true,
+	"Gemfile.lock":      true,
+}
+
+// hunkStart matches the new-file start line of a hunk header.
+var hunkStart = regexp.MustCompile(`^@@ -[0-9,]+ \+([0-9]+)`)
+
+// secretMatch is the location of a likely secret in a diff.
+type secretMatch struct {
+	File string
+	Line int // line number in the new file
+	Kind string
+}
+
+func (m secretMatch) String() string {
+	return fmt.Sprintf("%s:%d: %s", m.File, m.Line, m.Kind)
+}
+
+// scanSecrets reports added lines in diff that look like they contain
+// credentials. At most one match is reported per line.
+func scanSecrets(diff string) []secretMatch {
+	var matches []secretMatch
+	for _, f := range parseDiff(diff) {
+		checkEntropy := !checksumFiles[path.Base(f.path)]
+		for _, h := range f.hunks {
+			line := 0
+			for _, text := range strings.Split(strings.TrimSuffix(h, "\n"), "\n") {
+				switch {
+				case strings.HasPrefix(text, "@@"):
+					if m := hunkStart.FindStringSubmatch(text); m != nil {
+						line, _ = strconv.Atoi(m[1])
+					}
+				case strings.HasPrefix(text, "+"):
+					if kind := secretKind(text[1:], checkEntropy); kind != "" {
+						matches = append(matches, secretMatch{File: f.path, Line: line, Kind: kind})
+					}
+					line++
+				case strings.HasPrefix(text, "-"), strings.HasPrefix(text, `\`):
+					// Not in the new file
+				default:
+					line++
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// secretKind returns what kind of secret text appears to contain, or "".
+// Random-looking tokens are only reported if checkEntropy is set.
+func secretKind(text string, checkEntropy bool) string {
+	for _, p := range secretPatterns {
+		if p.re.MatchString(text) {
+			return p.kind
+		}
+	}
+	if !checkEntropy {
+		return ""
+	}
+	for _, tok := range tokenCandidate.FindAllString(text, -1) {
+		if hasLetterAndDigit(tok) && entropy(tok) >= minTokenEntropy {
+			return "high-entropy token"
+		}
+	}
+	return ""
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len(s))
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+func hasLetterAndDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789") &&
+		strings.ContainsAny(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+}
+
+// secretOptions holds the flags that control the secret scan.
+type secretOptions struct {
+	force  bool
+	noSend bool
+}
+
+func (o *secretOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.force, "force", false, "Skip the scan for secrets in the diff")
+	cmd.Flags().BoolVar(&o.noSend, "no-send-secrets", false, "Abort instead of asking if the diff appears to contain secrets")
+	cmd.MarkFlagsMutuallyExclusive("force", "no-send-secrets")
+}
+
+// check scans diff for secrets before it is sent to the AI. If any are
+// found it lists them on stderr and either aborts or, when stdin is a
+// terminal, asks the user on reader whether to continue. It returns false
+// if the diff must not be sent, with a KindCancelled error if the user
+// declined.
+func (o *secretOptions) check(reader *bufio.Reader, diff string) (bool, error) {
+	if o.force {
+		return true, nil
+	}
+
+	matches := scanSecrets(diff)
+	if len(matches) == 0 {
+		return true, nil
+	}
+
+	fmt.Fprintln(os.Stderr, "Warning: the diff appears to contain secrets:")
+	for _, m := range matches {
+		fmt.Fprintf(os.Stderr, "  %s\n", m)
+	}
+
+	if o.noSend {
+		return false, fmt.Errorf("diff not sent: possible secrets found (use --force to send anyway)")
+	}
+	if !stdinIsTerminal() {
+		return false, fmt.Errorf("diff not sent: possible secrets found and no terminal to confirm sending them (use --force to send anyway)")
+	}
+
+	fmt.Fprint(os.Stderr, "Send it to the AI anyway? [y/N]: ")
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer != "y" && answer != "yes" {
+		return false, &Error{Kind: KindCancelled, Msg: "diff not sent"}
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+// The fake credentials are split so that they are not themselves flagged
+// by secret scanners.
+const (
+	fakeAWSKey      = "AKIA" + "IOSFODNN7EXAMPLE"
+	fakeGitHubToken = "ghp_" + "abcdefghijklmnopqrstuvwxyz0123456789"
+	fakeSlackToken  = "xoxb-" + "1234567890-abcdefghij"
+	fakeAPIKey      = "sk-" + "proj-abcdefghij0123456789"
+	fakePrivateKey  = "-----BEGIN RSA " + "PRIVATE KEY-----"
+	// randomToken has 32 distinct characters, 5 bits of entropy each.
+	randomToken = "q8Zk3LmP0vR7xT2wN9bY5cF1hJ6dG4sA"
+)
+
+func TestSecretKind(t *testing.T) {
+	for _, tt := range []struct {
+		text, want string
+	}{
+		{`aws_access_key_id = ` + fakeAWSKey, "AWS access key"},
+		{fakePrivateKey, "private key"},
+		{`token: "` + fakeGitHubToken + `"`, "GitHub token"},
+		{`SLACK=` + fakeSlackToken, "Slack token"},
+		{`OPENAI_API_KEY=` + fakeAPIKey, "API key"},
+		{`secret = "` + randomToken + `"`, "high-entropy token"},
+		// Hex digests stay under the entropy threshold
+		{`sum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"`, ""},
+		{`// A long comment without anything secret in it at all`, ""},
+		// A long run of letters with no digit is never a token
+		{`name := "abcdefghijklmnopqrstuvwxyzABCDEFGH"`, ""},
+	} {
+		if got := secretKind(tt.text, true); got != tt.want {
+			t.Errorf("secretKind(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScanSecretsLineNumbers(t *testing.T) {
+	diff := "diff --git a/config.go b/config.go\n" +
+		"index 1111111..2222222 100644\n" +
+		"--- a/config.go\n" +
+		"+++ b/config.go\n" +
+		"@@ -1,3 +1,4 @@\n" +
+		" package config\n" +
+		"-const old = 1\n" +
+		"+const new = 1\n" +
+		"+const key = \"" + fakeAWSKey + "\"\n" +
+		" \n" +
+		"@@ -40,4 +41,5 @@ func load() {\n" +
+		" \tx := 1\n" +
+		"-\ty := 2\n" +
+		"+\ty := 3\n" +
+		"+\ttoken := \"" + fakeGitHubToken + "\"\n" +
+		" \treturn\n" +
+		"+\tapiKey := \"" + fakeAPIKey + "\"\n" +
+		"\\ No newline at end of file\n" +
+		"diff --git a/id_rsa b/id_rsa\n" +
+		"new file mode 100644\n" +
+		"--- /dev/null\n" +
+		"+++ b/id_rsa\n" +
+		"@@ -0,0 +1,2 @@\n" +
+		"+" + fakePrivateKey + "\n" +
+		"+MIIEpAIBAAKCAQEA\n"
+
+	got := scanSecrets(diff)
+	want := []secretMatch{
+		{File: "config.go", Line: 3, Kind: "AWS access key"},
+		{File: "config.go", Line: 43, Kind: "GitHub token"},
+		{File: "config.go", Line: 45, Kind: "API key"},
+		{File: "id_rsa", Line: 1, Kind: "private key"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("scanSecrets found\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestScanSecretsChecksumFiles(t *testing.T) {
+	lockfile := func(path, line string) string {
+		return "diff --git a/" + path + " b/" + path + "\n" +
+			"--- a/" + path + "\n" +
+			"+++ b/" + path + "\n" +
+			"@@ -1 +1,2 @@\n" +
+			" example.com/a v1.0.0 h1:old=\n" +
+			"+" + line + "\n"
+	}
+
+	// Hashes in lockfiles look random, but are not secrets
+	for _, path := range []string{"go.sum", "web/package-lock.json"} {
+		if got := scanSecrets(lockfile(path, "example.com/b v1.2.0 h1:"+randomToken+"=")); len(got) != 0 {
+			t.Errorf("%s: found %v in a checksum", path, got)
+		}
+	}
+	// The same line elsewhere is reported
+	if got := scanSecrets(lockfile("notes.txt", "example.com/b v1.2.0 h1:"+randomToken+"=")); len(got) != 1 {
+		t.Errorf("notes.txt: found %v, want the token", got)
+	}
+	// Known credential formats are reported even in lockfiles
+	got := scanSecrets(lockfile("go.sum", fakeAWSKey))
+	if want := []secretMatch{{File: "go.sum", Line: 2, Kind: "AWS access key"}}; !slices.Equal(got, want) {
+		t.Errorf("go.sum with a key: found %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ai/internal/fileutil"
+)
+
+// exchange is a single question and response in an ask session.
+type exchange struct {
+	Question string    `json:"question"`
+	Response string    `json:"response"`
+	Time     time.Time `json:"time"`
+}
+
+// session is the conversation that `ask --continue` builds on. Every
+// session is also kept in the history directory under its ID.
+type session struct {
+	// ID names the session in `arc-ai history`. It is assigned when the
+	// session is first saved, and missing from sessions saved before
+	// history was kept.
+	ID        string     `json:"id,omitempty"`
+	Exchanges []exchange `json:"exchanges"`
+}
+
+// sessionIDFormat formats the time a session started as its ID, so that
+// IDs sort by age.
+const sessionIDFormat = "20060102-150405"
+
+// cacheDir returns the arc-ai cache directory, e.g. ~/.cache/arc-ai.
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve cache dir: %w", err)
+	}
+	return filepath.Join(dir, "arc-ai"), nil
+}
+
+func sessionPath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "session.json"), nil
+}
+
+// historyDir returns the directory that keeps every session.
+func historyDir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+// loadSession reads the stored session. A missing file yields an empty session.
+func loadSession() (*session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &session{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session: %w", err)
+	}
+
+	return parseSession(path, data)
+}
+
+func parseSession(path string, data []byte) (*session, error) {
+	var s session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse session %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// save writes the session, as the current one and to the history,
+// atomically so a crash never leaves a partial file.
+func (s *session) save() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	dir, err := historyDir()
+	if err != nil {
+		return err
+	}
+	if s.ID == "" {
+		s.ID = newSessionID(dir, s.started())
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+
+	for _, p := range []string{path, filepath.Join(dir, s.ID+".json")} {
+		if err := fileutil.WriteAtomic(p, data); err != nil {
+			return fmt.Errorf("write session: %w", err)
+		}
+	}
+	return nil
+}
+
+// newSessionID returns an ID for a session started at t that no session
+// in the history directory dir has yet.
+func newSessionID(dir string, t time.Time) string {
+	base := t.Format(sessionIDFormat)
+	id := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(dir, id+".json")); errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// started returns when the session's first exchange took place, or now
+// for an empty session.
+func (s *session) started() time.Time {
+	if len(s.Exchanges) == 0 {
+		return time.Now()
+	}
+	return s.Exchanges[0].Time
+}
+
+// historySessions returns the sessions in the history, newest first.
+func historySessions() ([]*session, error) {
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	var sessions []*session
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		s, err := historySession(id)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].started().After(sessions[j].started())
+	})
+	return sessions, nil
+}
+
+// historySession reads the session with the given ID from the history.
+func historySession(id string) (*session, error) {
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return nil, fmt.Errorf("invalid session ID %q", id)
+	}
+
+	path := filepath.Join(dir, id+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no session %q in the history (see arc-ai history)", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session: %w", err)
+	}
+	s, err := parseSession(path, data)
+	if err != nil {
+		return nil, err
+	}
+	s.ID = id
+	return s, nil
+}
+
+// clearHistory removes every stored session, including the current one.
+func clearHistory() error {
+	dir, err := historyDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("clear history: %w", err)
+	}
+	return resetSession()
+}
+
+// resetSession removes the stored session.
+func resetSession() error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reset session: %w", err)
+	}
+	return nil
+}
+
+// prompt builds the prompt for question, including prior exchanges as context.
+func (s *session) prompt(question string) string {
+	if len(s.Exchanges) == 0 {
+		return question
+	}
+
+	var b strings.Builder
+	b.WriteString("Continue this conversation. Previous exchanges:\n\n")
+	for _, ex := range s.Exchanges {
+		fmt.Fprintf(&b, "User: %s\n\nAssistant: %s\n\n", ex.Question, ex.Response)
+	}
+	fmt.Fprintf(&b, "User: %s", question)
+	return b.String()
+}
+
+// add records an exchange in the session.
+func (s *session) add(question, response string) {
+	s.Exchanges = append(s.Exchanges, exchange{
+		Question: question,
+		Response: response,
+		Time:     time.Now(),
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// interruptGrace is how long an interrupted command has to stop its AI
+// requests before arc-ai exits anyway.
+const interruptGrace = 2 * time.Second
+
+var (
+	// inflight counts the AI requests being sent.
+	inflight atomic.Int32
+	// foreground counts the interactive programs, such as the editor, that
+	// have the terminal; they handle Ctrl-C themselves.
+	foreground atomic.Int32
+)
+
+// inForeground marks an interactive program as running until the returned
+// function is called.
+func inForeground() func() {
+	foreground.Add(1)
+	return func() { foreground.Add(-1) }
+}
+
+// handleInterrupts returns a context that is cancelled by SIGINT or
+// SIGTERM, which kills provider CLIs and aborts HTTP requests. With no AI
+// request in flight, such as at a prompt, the process exits at once;
+// otherwise it exits when the command has not returned within
+// interruptGrace, or on a second signal. caught returns the signal
+// received, if any, and stop ends the handling.
+func handleInterrupts() (ctx context.Context, caught func() os.Signal, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	var got atomic.Value
+	done := make(chan struct{})
+	go func() {
+		for {
+			var sig os.Signal
+			select {
+			case sig = <-sigs:
+			case <-done:
+				return
+			}
+			if sig == os.Interrupt && foreground.Load() > 0 {
+				continue
+			}
+
+			got.Store(sig)
+			cancel()
+			if inflight.Load() > 0 {
+				select {
+				case <-sigs:
+				case <-time.After(interruptGrace):
+				case <-done:
+					return
+				}
+			}
+			os.Exit(signalExitCode(sig))
+		}
+	}()
+
+	caught = func() os.Signal {
+		sig, _ := got.Load().(os.Signal)
+		return sig
+	}
+	stop = func() {
+		signal.Stop(sigs)
+		close(done)
+		cancel()
+	}
+	return ctx, caught, stop
+}
+
+// signalExitCode returns the exit code that shells report for a process
+// killed by sig: 128 plus the signal number, such as ExitInterrupted.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return ExitInterrupted
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// spinnerFrames are drawn in turn, one per spinnerInterval.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+// spinner animates a message on a terminal while the caller waits. A nil
+// *spinner does nothing, so it can be disabled by not starting one.
+type spinner struct {
+	f    *os.File
+	msg  string
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// startSpinner draws msg with an animation and elapsed time on f until
+// Stop is called or ctx is done. It returns nil, drawing nothing, if f is
+// not a terminal.
+func startSpinner(ctx context.Context, f *os.File, msg string) *spinner {
+	if !isTerminal(f) {
+		return nil
+	}
+	s := &spinner{f: f, msg: msg, stop: make(chan struct{}), done: make(chan struct{})}
+	go s.run(ctx)
+	return s
+}
+
+func (s *spinner) run(ctx context.Context) {
+	defer close(s.done)
+	// Clear the line so that whatever is printed next starts cleanly
+	defer fmt.Fprint(s.f, "\r\033[K")
+
+	start := time.Now()
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 0; ; frame++ {
+		elapsed := ""
+		if d := time.Since(start); d >= time.Second {
+			elapsed = fmt.Sprintf(" (%ds)", int(d.Seconds()))
+		}
+		fmt.Fprintf(s.f, "\r%s %s%s\033[K", spinnerFrames[frame%len(spinnerFrames)], s.msg, elapsed)
+
+		select {
+		case <-s.stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop ends the animation and clears its line. It is safe to call more
+// than once.
+func (s *spinner) Stop() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+}
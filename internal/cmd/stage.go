@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// workingChange is a file with changes that are not staged, as reported by
+// git status.
+type workingChange struct {
+	// status is the two-letter git status code, such as " M" or "??".
+	status string
+	path   string
+}
+
+// workingChanges lists the files with unstaged changes, including
+// untracked files, with paths relative to the top of the working tree.
+func workingChanges(ctx context.Context) ([]workingChange, error) {
+	// Not git(), whose trimming would eat the leading space of " M"
+	out, err := exec.CommandContext(ctx, "git", "status", "--porcelain", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+
+	var changes []workingChange
+	fields := strings.Split(string(out), "\x00")
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if len(f) < 4 {
+			continue
+		}
+		status, path := f[:2], f[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			// The original path of a rename or copy follows as its own field
+			i++
+		}
+		if status[1] != ' ' {
+			changes = append(changes, workingChange{status: status, path: path})
+		}
+	}
+	return changes, nil
+}
+
+// pickChanges shows changes as a numbered list and asks which to stage. It
+// returns the chosen paths, or ok false if the user chose none.
+func pickChanges(reader *bufio.Reader, changes []workingChange) (paths []string, ok bool) {
+	fmt.Println("Nothing is staged. Changed files:")
+	for i, c := range changes {
+		fmt.Printf("  [%d] %s %s\n", i+1, c.status, c.path)
+	}
+
+	for {
+		fmt.Print("Stage which files? (e.g. 1 3 5-7, a for all, Enter to cancel): ")
+		response, err := reader.ReadString('\n')
+		response = strings.TrimSpace(response)
+		if response == "" {
+			return nil, false
+		}
+
+		picked, perr := parseSelection(response, len(changes))
+		if perr == nil {
+			for _, i := range picked {
+				paths = append(paths, changes[i].path)
+			}
+			return paths, true
+		}
+		fmt.Printf("%v\n", perr)
+		if err != nil {
+			return nil, false
+		}
+	}
+}
+
+// parseSelection parses a list of 1-based numbers and ranges, such as
+// "1 3,5-7", or "a" for all, into sorted 0-based indexes below n.
+func parseSelection(s string, n int) ([]int, error) {
+	if strings.EqualFold(s, "a") || strings.EqualFold(s, "all") {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+
+	picked := make([]bool, n)
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' }) {
+		lo, hi, isRange := strings.Cut(field, "-")
+		first, err := strconv.Atoi(lo)
+		last := first
+		if err == nil && isRange {
+			last, err = strconv.Atoi(hi)
+		}
+		if err != nil || first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("%q is not a file number or range between 1 and %d", field, n)
+		}
+		for i := first; i <= last; i++ {
+			picked[i-1] = true
+		}
+	}
+
+	var indexes []int
+	for i, p := range picked {
+		if p {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no file numbers given")
+	}
+	return indexes, nil
+}
+
+// stagePaths stages paths, given relative to the top of the working tree.
+func stagePaths(ctx context.Context, paths []string) error {
+	args := []string{"add", "--"}
+	for _, p := range paths {
+		args = append(args, ":(top,literal)"+p)
+	}
+	_, err := git(ctx, args...)
+	return err
+}
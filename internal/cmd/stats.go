@@ -0,0 +1,118 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-ai/ai"
+)
+
+// requestStats totals the token usage and latency of a command's answered
+// AI requests for --stats. It is safe for concurrent use.
+type requestStats struct {
+	mu       sync.Mutex
+	requests int
+	usage    ai.Usage
+	cached   int
+	elapsed  time.Duration
+	// reported is set once the totals are part of the command's output, so
+	// they are not printed again.
+	reported bool
+}
+
+// statsReport is the stats object of --output json and yaml.
+type statsReport struct {
+	Requests     int   `json:"requests" yaml:"requests"`
+	InputTokens  int   `json:"input_tokens" yaml:"input_tokens"`
+	OutputTokens int   `json:"output_tokens" yaml:"output_tokens"`
+	Estimated    bool  `json:"estimated,omitempty" yaml:"estimated,omitempty"`
+	Cached       int   `json:"cached,omitempty" yaml:"cached,omitempty"`
+	DurationMS   int64 `json:"duration_ms" yaml:"duration_ms"`
+}
+
+// middleware returns an ai.Middleware that adds each answered request to s.
+func (s *requestStats) middleware() ai.Middleware {
+	return func(next ai.Handler) ai.Handler {
+		return func(ctx context.Context, req ai.Request) (ai.Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, err
+			}
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.requests++
+			s.usage.InputTokens += resp.Usage.InputTokens
+			s.usage.OutputTokens += resp.Usage.OutputTokens
+			// One estimate makes the totals estimates
+			s.usage.Estimated = s.usage.Estimated || resp.Usage.Estimated
+			if resp.Cached {
+				s.cached++
+			}
+			s.elapsed += time.Since(start)
+			return resp, nil
+		}
+	}
+}
+
+// report returns the totals for --output json and marks them reported; nil
+// if s is nil or no request was answered.
+func (s *requestStats) report() *statsReport {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests == 0 {
+		return nil
+	}
+	s.reported = true
+	return &statsReport{
+		Requests:     s.requests,
+		InputTokens:  s.usage.InputTokens,
+		OutputTokens: s.usage.OutputTokens,
+		Estimated:    s.usage.Estimated,
+		Cached:       s.cached,
+		DurationMS:   s.elapsed.Milliseconds(),
+	}
+}
+
+// print writes the totals to w as a footer line, such as
+// "stats: 1520 input tokens, 64 output tokens, 2.413s", unless s is nil or
+// they were reported already. Estimated counts, from providers that do not
+// report usage, are marked with ~.
+func (s *requestStats) print(w io.Writer) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reported || s.requests == 0 {
+		return
+	}
+
+	approx := ""
+	if s.usage.Estimated {
+		approx = "~"
+	}
+	var parts []string
+	if s.requests > 1 {
+		parts = append(parts, fmt.Sprintf("%d requests", s.requests))
+	}
+	parts = append(parts,
+		fmt.Sprintf("%s%d input tokens", approx, s.usage.InputTokens),
+		fmt.Sprintf("%s%d output tokens", approx, s.usage.OutputTokens))
+	if s.cached > 0 {
+		parts = append(parts, fmt.Sprintf("%d cached", s.cached))
+	}
+	parts = append(parts, s.elapsed.Round(time.Millisecond).String())
+	fmt.Fprintf(w, "stats: %s\n", strings.Join(parts, ", "))
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Commit message styles, selected with --style or commit-format.
+const (
+	styleConventional = "conventional"
+	styleGitmoji      = "gitmoji"
+	stylePlain        = "plain"
+)
+
+// commitStyle is the prompt guidance for one commit message style.
+type commitStyle struct {
+	name string
+	// rules describes the subject line format to the AI.
+	rules string
+}
+
+// gitmojis maps change types to their gitmoji prefix.
+var gitmojis = []struct {
+	emoji, kind string
+}{
+	{"✨", "new feature"},
+	{"🐛", "bug fix"},
+	{"📝", "documentation"},
+	{"♻️", "refactor"},
+	{"⚡️", "performance"},
+	{"✅", "tests"},
+	{"🔧", "configuration"},
+	{"⬆️", "dependency upgrade"},
+	{"🔥", "removed code or files"},
+	{"🎨", "formatting or structure"},
+	{"🚑️", "critical hotfix"},
+	{"🔒️", "security fix"},
+}
+
+// commitStyles lists the supported styles.
+var commitStyles = []commitStyle{
+	{
+		name:  styleConventional,
+		rules: "Use conventional commit format (feat:, fix:, docs:, refactor:, etc.).",
+	},
+	{
+		name:  stylePlain,
+		rules: "Write the subject as a plain imperative sentence (e.g. \"Add retry support\"), with no type prefix or emoji.",
+	},
+	{
+		name:  styleGitmoji,
+		rules: gitmojiRules(),
+	},
+}
+
+func gitmojiRules() string {
+	var b strings.Builder
+	b.WriteString("Use gitmoji format: start the subject with the one emoji matching the type of change,\nfollowed by a space and an imperative summary, with no other type prefix.\n")
+	for _, g := range gitmojis {
+		fmt.Fprintf(&b, "%s %s\n", g.emoji, g.kind)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// findStyle returns the named commit style.
+func findStyle(name string) (commitStyle, error) {
+	for _, s := range commitStyles {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return commitStyle{}, fmt.Errorf("unknown commit style %q (valid: %s)", name, strings.Join(styleNames(), ", "))
+}
+
+func styleNames() []string {
+	names := make([]string, len(commitStyles))
+	for i, s := range commitStyles {
+		names[i] = s.name
+	}
+	return names
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// defaultChunkTokens is the default size of the parts of a diff that
+// commit --summarize-large summarizes one at a time.
+const defaultChunkTokens = 6000
+
+// chunkDiff splits diff by file into parts of at most maxTokens, keeping
+// the files in order. A file too large for a part of its own is truncated
+// with truncateDiff.
+func chunkDiff(diff string, maxTokens int) []string {
+	var chunks []string
+	var cur strings.Builder
+	used := 0
+	for _, f := range parseDiff(diff) {
+		text := f.String()
+		cost := estimateTokens(text)
+		if cost > maxTokens {
+			text = truncateDiff(text, maxTokens)
+			cost = estimateTokens(text)
+		}
+		if used > 0 && used+cost > maxTokens {
+			chunks = append(chunks, cur.String())
+			cur.Reset()
+			used = 0
+		}
+		cur.WriteString(text)
+		used += cost
+	}
+	if used > 0 {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// chunkPrompt asks for a summary of part n of total of a larger diff.
+func chunkPrompt(chunk string, n, total int) string {
+	return fmt.Sprintf(`Summarize the following part (%d of %d) of a large git diff, for someone
+who will write its commit message from the summaries of all the parts.
+For each file, say in one or two short bullet points what changed and
+why it matters. Mention new or removed functions, types, and flags by
+name. Do not write a commit message.
+
+Diff:
+%s
+
+Respond with ONLY the bullet points.`, n, total, chunk)
+}
+
+// summarizeDiff summarizes each part of diff and returns the summaries,
+// to be sent in place of the diff. Every part is sent, so the confirmation
+// of large requests covers them all. ok is false if the user declined or
+// --estimate-only was given.
+func (o *commitOptions) summarizeDiff(ctx context.Context, reader *bufio.Reader, diff string) (summaries string, ok bool, err error) {
+	chunks := chunkDiff(diff, o.chunkTokens)
+	prompts := make([]string, len(chunks))
+	for i, c := range chunks {
+		prompts[i] = chunkPrompt(c, i+1, len(chunks))
+	}
+	if ok, err := o.ai.preflight(reader, strings.Join(prompts, "")); !ok {
+		return "", false, err
+	}
+
+	var b strings.Builder
+	progress := o.ai.progress
+	defer func() { o.ai.progress = progress }()
+	for i, prompt := range prompts {
+		if progress != "" {
+			o.ai.progress = fmt.Sprintf("Summarizing part %d of %d", i+1, len(prompts))
+		} else {
+			o.status(fmt.Sprintf("Summarizing part %d of %d...", i+1, len(prompts)))
+		}
+		summary, err := askAI(ctx, o.ai.request(prompt))
+		if err != nil {
+			return "", false, fmt.Errorf("summarize part %d of %d: %w", i+1, len(prompts), err)
+		}
+		fmt.Fprintf(&b, "Part %d of %d:\n%s\n\n", i+1, len(prompts), strings.TrimSpace(summary))
+	}
+
+	o.summarized = true
+	// The summaries of a vast diff could themselves be too long
+	return truncateText(strings.TrimSpace(b.String())+"\n", o.maxTokens), true, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/output"
+)
+
+// Summary lengths accepted by summary --length.
+const (
+	lengthShort  = "short"
+	lengthMedium = "medium"
+	lengthBullet = "bullet"
+)
+
+// summaryLengths describes each summary length to the AI.
+var summaryLengths = map[string]string{
+	lengthShort:  "in one or two sentences",
+	lengthMedium: "in one short paragraph",
+	lengthBullet: "as a bulleted list of its main points, one line each",
+}
+
+// textSummary is the structured form of a summary response.
+type textSummary struct {
+	Summary   string   `json:"summary"`
+	KeyPoints []string `json:"keypoints,omitempty"`
+}
+
+func newSummaryCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var length string
+	var maxTokens int
+	var out output.OutputOptions
+
+	cmd := &cobra.Command{
+		Use:   "summary [file]",
+		Short: "Summarize text",
+		Long: `Summarize any text, such as notes, logs, or documentation.
+
+The text is read from the file argument, or from stdin if none is given,
+and truncated to fit within --max-tokens. --length is short (a sentence or
+two), medium (a paragraph), or bullet (a list of the main points).
+
+--output json prints an object with the summary and, when the AI gives
+them, its key points.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := out.Resolve(); err != nil {
+				return err
+			}
+			describe, ok := summaryLengths[length]
+			if !ok {
+				return fmt.Errorf("unknown --length %q (valid: %s, %s, %s)", length, lengthShort, lengthMedium, lengthBullet)
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			text, err := readFileOrStdin(path)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return fmt.Errorf("no text provided")
+			}
+
+			text = truncateText(text, maxTokens)
+
+			header := ""
+			if path != "" {
+				header = fmt.Sprintf("File: %s\n", path)
+			}
+
+			if out.Is(output.OutputJSON) {
+				prompt := fmt.Sprintf(`Summarize the following text.
+%sRespond with ONLY a JSON object, no explanations, with:
+- "summary": the summary, written %s
+- "keypoints": an array of strings for its most important points, if any
+
+Text:
+%s`, header, describe, text)
+
+				if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+					return err
+				}
+
+				response, err := askAI(ctx, aiOpts.request(prompt))
+				if err != nil {
+					return err
+				}
+
+				var result textSummary
+				if err := decodeResponseJSON(response, &result); err != nil {
+					return err
+				}
+				return output.JSON(result)
+			}
+
+			prompt := fmt.Sprintf(`Summarize the following text %s. Keep the facts that matter and
+leave out the rest.
+%s
+Text:
+%s
+
+Respond with ONLY the summary.`, describe, header, text)
+
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), prompt); !ok {
+				return err
+			}
+
+			response, err := askAI(ctx, aiOpts.request(prompt))
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(response)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringVar(&length, "length", lengthMedium, "Summary length: short, medium, or bullet")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the text sent to the AI")
+	_ = cmd.RegisterFlagCompletionFunc("length", cobra.FixedCompletions(
+		[]string{lengthShort, lengthMedium, lengthBullet}, cobra.ShellCompDirectiveNoFileComp))
+	out.AddOutputFlags(cmd, output.OutputTable)
+	registerOutputCompletion(cmd)
+
+	return cmd
+}
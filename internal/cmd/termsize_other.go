@@ -0,0 +1,18 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package cmd
+
+import (
+	"os"
+	"strconv"
+)
+
+// terminalWidth returns $COLUMNS where the terminal cannot be asked for
+// its size, or 0 if that is not set either.
+func terminalWidth(f *os.File) int {
+	n, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	return n
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// testTarget is a Go source file to generate tests for.
+type testTarget struct {
+	path string
+	// funcs are the functions to test, as Name or Type.Method; empty means
+	// every function in the file.
+	funcs []string
+}
+
+func newTestCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var funcs []string
+	var pkg string
+	var dryRun, force bool
+	var maxTokens int
+
+	cmd := &cobra.Command{
+		Use:   "test [file]",
+		Short: "Generate Go unit tests",
+		Long: `Generate table-driven Go tests for the functions in a file.
+
+With a file argument, tests cover every function in it, or only those
+named with --func (Name, or Type.Method for methods). Without one, tests
+cover the functions changed in the staged diff, one test file per
+changed source file.
+
+The whole source file is sent as context so the tests use its real
+signatures. Tests for foo.go are written to foo_test.go; an existing test
+file is only replaced with --force. --dry-run prints the tests instead.
+
+The package clause is detected from the source file; use --package to
+choose another, such as foo_test for black-box tests.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+			if !cmd.Flags().Changed("max-tokens") {
+				maxTokens = cfg.MaxTokens
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var targets []testTarget
+			if len(args) > 0 {
+				targets = []testTarget{{path: args[0], funcs: funcs}}
+			} else {
+				if len(funcs) > 0 {
+					return fmt.Errorf("--func needs a file argument")
+				}
+				if err := requireRepo(ctx); err != nil {
+					return err
+				}
+				targets, err = stagedTestTargets(ctx)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Check every destination before asking for any tests
+			if !dryRun && !force {
+				for _, t := range targets {
+					if _, err := os.Stat(testFilePath(t.path)); err == nil {
+						return fmt.Errorf("%s already exists; use --force to replace it or --dry-run to print the tests", testFilePath(t.path))
+					}
+				}
+			}
+
+			reader := bufio.NewReader(os.Stdin)
+			for _, t := range targets {
+				tests, err := generateTests(ctx, &aiOpts, reader, t, pkg, maxTokens)
+				if err != nil || tests == "" {
+					return err
+				}
+
+				if dryRun {
+					if len(targets) > 1 {
+						fmt.Printf("// %s\n", testFilePath(t.path))
+					}
+					fmt.Println(tests)
+					continue
+				}
+
+				if err := os.WriteFile(testFilePath(t.path), []byte(tests+"\n"), 0o644); err != nil {
+					return fmt.Errorf("write tests: %w", err)
+				}
+				fmt.Printf("Wrote %s\n", testFilePath(t.path))
+			}
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringSliceVar(&funcs, "func", nil, "Function to test, as Name or Type.Method (repeatable)")
+	cmd.Flags().StringVar(&pkg, "package", "", "Package clause for the test file (default: the source file's package)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the tests instead of writing them")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing test file")
+	cmd.Flags().IntVar(&maxTokens, "max-tokens", defaultMaxTokens, "Token budget for the source sent to the AI")
+
+	return cmd
+}
+
+// generateTests asks the AI for tests for t. It returns "" without an error
+// if the request was not sent.
+func generateTests(ctx context.Context, aiOpts *aiOptions, reader *bufio.Reader, t testTarget, pkg string, maxTokens int) (string, error) {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", t.path, err)
+	}
+	src := string(data)
+
+	if pkg == "" {
+		f, err := parser.ParseFile(token.NewFileSet(), t.path, src, parser.PackageClauseOnly)
+		if err != nil {
+			return "", fmt.Errorf("parse %s: %w", t.path, err)
+		}
+		pkg = f.Name.Name
+	}
+
+	what := "every function in " + t.path
+	if len(t.funcs) > 0 {
+		what = fmt.Sprintf("these functions in %s: %s", t.path, strings.Join(t.funcs, ", "))
+	}
+
+	prompt := fmt.Sprintf(`Write Go unit tests for %s.
+Use table-driven tests with t.Run subtests and only the standard library.
+Start the file with "package %s" and call the functions exactly as declared
+in the source below, so the tests compile.
+Respond with ONLY the contents of the _test.go file, no explanations.
+
+%s`, what, pkg, contextBlock(t.path, truncateText(src, maxTokens)))
+
+	if ok, err := aiOpts.preflight(reader, prompt); !ok {
+		return "", err
+	}
+
+	response, err := askAI(ctx, aiOpts.request(prompt))
+	if err != nil {
+		return "", err
+	}
+
+	tests := stripCodeFence(response)
+	f, err := parser.ParseFile(token.NewFileSet(), testFilePath(t.path), tests, parser.PackageClauseOnly)
+	if err != nil {
+		return "", fmt.Errorf("AI response is not a Go file: %w", err)
+	}
+	if f.Name.Name != pkg {
+		// Offsets are 1-based
+		start, end := int(f.Name.Pos())-1, int(f.Name.End())-1
+		tests = tests[:start] + pkg + tests[end:]
+	}
+	return tests, nil
+}
+
+// testFilePath returns the test file for a Go source file.
+func testFilePath(path string) string {
+	return strings.TrimSuffix(path, ".go") + "_test.go"
+}
+
+// stagedTestTargets returns the staged Go source files with the functions
+// whose bodies the staged changes touch.
+func stagedTestTargets(ctx context.Context) ([]testTarget, error) {
+	diff, err := gitDiff(ctx, true, diffFormat{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(diff) == 0 {
+		return nil, ErrNoStagedChanges
+	}
+
+	root, err := git(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []testTarget
+	for _, f := range parseDiff(diff) {
+		if !strings.HasSuffix(f.path, ".go") || strings.HasSuffix(f.path, "_test.go") {
+			continue
+		}
+
+		path := filepath.Join(root, f.path)
+		if rel, err := filepath.Rel(wd, path); err == nil {
+			path = rel
+		}
+		funcs, err := changedFuncs(path, changedLines(f))
+		if errors.Is(err, os.ErrNotExist) {
+			// Deleted
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(funcs) > 0 {
+			targets = append(targets, testTarget{path: path, funcs: funcs})
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, &Error{Kind: KindNoChanges, Msg: "no Go functions changed in the staged changes"}
+	}
+	return targets, nil
+}
+
+// changedLines returns the new-file line numbers that f adds to, or next to
+// which it removes lines.
+func changedLines(f diffFile) map[int]bool {
+	lines := map[int]bool{}
+	for _, h := range f.hunks {
+		line := 0
+		for _, text := range strings.Split(strings.TrimSuffix(h, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(text, "@@"):
+				if m := hunkStart.FindStringSubmatch(text); m != nil {
+					line, _ = strconv.Atoi(m[1])
+				}
+			case strings.HasPrefix(text, "+"):
+				lines[line] = true
+				line++
+			case strings.HasPrefix(text, "-"):
+				lines[line] = true
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file"
+			default:
+				line++
+			}
+		}
+	}
+	return lines
+}
+
+// changedFuncs returns the functions in the Go file at path that span any
+// of the given lines, as Name or Type.Method.
+func changedFuncs(path string, lines map[int]bool) ([]string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	var funcs []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start, end := fset.Position(fn.Pos()).Line, fset.Position(fn.End()).Line
+		for line := start; line <= end; line++ {
+			if lines[line] {
+				funcs = append(funcs, funcName(fn))
+				break
+			}
+		}
+	}
+	return funcs, nil
+}
+
+// funcName returns fn's name, qualified by its receiver type for methods.
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	// Generic receivers such as List[T]
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
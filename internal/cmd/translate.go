@@ -0,0 +1,203 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultTranslateTokens is the default size of the parts a text is
+// translated in. The translation of a part is about as long as the part,
+// so this leaves it room within the response limits of the providers.
+const defaultTranslateTokens = 2000
+
+func newTranslateCmd() *cobra.Command {
+	var aiOpts aiOptions
+	var to, from string
+	var write bool
+	var chunkTokens int
+
+	cmd := &cobra.Command{
+		Use:   "translate [file]",
+		Short: "Translate text or markdown to another language",
+		Long: `Translate a file, or stdin if none is given, to the language named by
+--to, as a name or BCP-47 tag such as fr or pt-BR.
+
+Markdown structure is kept: headings, lists, tables, links, and emphasis
+stay as they are around the translated text, and code blocks, inline
+code, and URLs are not translated. The source language is detected
+unless --from names it.
+
+The translation is printed, or with --write replaces the file. Long texts
+are translated in parts of at most --chunk-tokens, split between
+paragraphs, so nothing is truncated.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if write && len(args) == 0 {
+				return fmt.Errorf("--write requires a file")
+			}
+			if chunkTokens < 1 {
+				return fmt.Errorf("--chunk-tokens must be at least 1")
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				return err
+			}
+			aiOpts.resolve(cmd, cfg)
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			path := ""
+			if len(args) > 0 {
+				path = args[0]
+			}
+			text, err := readFileOrStdin(path)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return fmt.Errorf("no text provided")
+			}
+
+			chunks := splitParagraphs(text, chunkTokens)
+			prompts := make([]string, len(chunks))
+			for i, c := range chunks {
+				prompts[i] = translatePrompt(c, from, to)
+			}
+			if ok, err := aiOpts.preflight(bufio.NewReader(os.Stdin), strings.Join(prompts, "")); !ok {
+				return err
+			}
+
+			parts := make([]string, len(prompts))
+			progress := aiOpts.progress
+			for i, prompt := range prompts {
+				if progress != "" && len(prompts) > 1 {
+					aiOpts.progress = fmt.Sprintf("Translating part %d of %d", i+1, len(prompts))
+				}
+				response, err := askAI(ctx, aiOpts.request(prompt))
+				if err != nil {
+					if len(prompts) > 1 {
+						return fmt.Errorf("translate part %d of %d: %w", i+1, len(prompts), err)
+					}
+					return err
+				}
+				// A text that is itself one code block comes back fenced
+				if !strings.HasPrefix(strings.TrimSpace(chunks[i]), "```") {
+					response = unwrapCodeFence(response)
+				}
+				parts[i] = strings.TrimSpace(response)
+			}
+			translated := strings.Join(parts, "\n\n")
+			if strings.HasSuffix(text, "\n") {
+				translated += "\n"
+			}
+
+			if write {
+				info, err := os.Stat(path)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(path, []byte(translated), info.Mode().Perm()); err != nil {
+					return fmt.Errorf("write %s: %w", path, err)
+				}
+				fmt.Printf("Translated %s to %s\n", path, to)
+				return nil
+			}
+
+			fmt.Print(translated)
+			return nil
+		},
+	}
+
+	aiOpts.addFlags(cmd)
+	cmd.Flags().StringVar(&to, "to", "", "Language to translate to, as a name or BCP-47 tag (required)")
+	cmd.Flags().StringVar(&from, "from", "", "Language of the text (default: detected)")
+	cmd.Flags().BoolVar(&write, "write", false, "Replace the file with its translation instead of printing it")
+	cmd.Flags().IntVar(&chunkTokens, "chunk-tokens", defaultTranslateTokens, "Largest part of the text translated at once")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// translatePrompt asks for text to be translated from the language from,
+// or "" to detect it, to the language to.
+func translatePrompt(text, from, to string) string {
+	source := "Detect the language it is written in."
+	if from != "" {
+		source = fmt.Sprintf("It is written in the language %q.", from)
+	}
+	return fmt.Sprintf(`Translate the following text to the language %q. %s
+
+Keep the markdown structure exactly: headings, lists, tables, links,
+emphasis, and line breaks stay where they are, and only the text in them
+is translated. Do not translate code blocks, inline code, URLs, file
+paths, or HTML tags. Keep the tone and meaning; do not add or leave out
+anything.
+
+Text:
+%s
+
+Respond with ONLY the translation.`, to, source, text)
+}
+
+// splitParagraphs splits text into parts of at most maxTokens, between
+// paragraphs outside code fences. A paragraph too large for a part of its
+// own is a part by itself.
+func splitParagraphs(text string, maxTokens int) []string {
+	var paragraphs []string
+	var cur strings.Builder
+	fence := ""
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case trimmed == "":
+			if cur.Len() > 0 {
+				paragraphs = append(paragraphs, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteString(line + "\n")
+	}
+	if cur.Len() > 0 {
+		paragraphs = append(paragraphs, cur.String())
+	}
+
+	var parts []string
+	var part strings.Builder
+	used := 0
+	for _, p := range paragraphs {
+		cost := estimateTokens(p)
+		if used > 0 && used+cost > maxTokens {
+			parts = append(parts, strings.TrimRight(part.String(), "\n"))
+			part.Reset()
+			used = 0
+		}
+		if used > 0 {
+			part.WriteString("\n")
+		}
+		part.WriteString(p)
+		used += cost
+	}
+	if used > 0 {
+		parts = append(parts, strings.TrimRight(part.String(), "\n"))
+	}
+	return parts
+}
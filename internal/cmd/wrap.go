@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// listItem matches the indentation and marker of a list item, so that its
+// wrapped lines can hang under the item's text.
+var listItem = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+`)
+
+// wrapText word-wraps the prose in s to width columns. Fenced code blocks,
+// indented code, and table rows are left as they are, as are words longer
+// than width. A width of 0 or less leaves s unchanged.
+func wrapText(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	var out []string
+	fence := ""
+	for _, line := range strings.Split(s, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"):
+			fence = "```"
+		case strings.HasPrefix(trimmed, "~~~"):
+			fence = "~~~"
+		case utf8.RuneCountInString(line) > width && !preformatted(line):
+			out = append(out, wrapLine(line, width)...)
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// preformatted reports whether a line outside a fence must keep its
+// layout: indented code or a table row.
+func preformatted(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") ||
+		strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// wrapLine breaks one long line at spaces. Continuation lines are indented
+// to where the text of the first line starts.
+func wrapLine(line string, width int) []string {
+	prefix := listItem.FindString(line)
+	if prefix == "" {
+		prefix = line[:len(line)-len(strings.TrimLeft(line, " "))]
+	}
+	hang := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+
+	var lines []string
+	current, n := prefix, utf8.RuneCountInString(prefix)
+	empty := true
+	for _, word := range strings.Fields(line[len(prefix):]) {
+		w := utf8.RuneCountInString(word)
+		if !empty && n+1+w > width {
+			lines = append(lines, current)
+			current, n, empty = hang, len(hang), true
+		}
+		if !empty {
+			current += " "
+			n++
+		}
+		current += word
+		n += w
+		empty = false
+	}
+	return append(lines, current)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package ignore matches paths against patterns in the .gitignore syntax:
+// comments, negation with !, directory-only patterns ending in /, patterns
+// anchored by a /, and the wildcards *, ?, [...], and **.
+package ignore
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matcher reports whether paths are ignored. The zero value ignores
+// nothing.
+type Matcher struct {
+	rules []rule
+}
+
+type rule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Compile returns a Matcher for the patterns in text, as read from an
+// ignore file at the root of the tree.
+func Compile(text string) *Matcher {
+	m := &Matcher{}
+	m.Add("", text)
+	return m
+}
+
+// Add adds the patterns in text, as read from an ignore file in the
+// directory base: a slash-separated path relative to the root of the
+// tree, or "" for the root. Its patterns apply only to paths under base.
+// Patterns added later take precedence, so files in deeper directories
+// should be added after those above them.
+func (m *Matcher) Add(base, text string) {
+	base = strings.Trim(base, "/")
+	for _, line := range strings.Split(text, "\n") {
+		if r, ok := parseRule(base, line); ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+}
+
+// Match reports whether path, slash-separated and relative to the root of
+// the tree, is ignored. isDir says whether path is a directory. As in git,
+// a path inside an ignored directory is ignored even if a later pattern
+// would include it again.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	path = strings.Trim(path, "/")
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && m.match(path[:i], true) {
+			return true
+		}
+	}
+	return m.match(path, isDir)
+}
+
+// match applies the rules to path itself; the last matching rule wins.
+func (m *Matcher) match(path string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.re.MatchString(path) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// parseRule parses one line of an ignore file in the directory base. ok is
+// false for blank lines and comments.
+func parseRule(base, line string) (r rule, ok bool) {
+	line = strings.TrimSuffix(line, "\r")
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	// A slash anywhere but the end anchors the pattern to base; otherwise
+	// it matches a name at any depth
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if base != "" {
+		b.WriteString(regexp.QuoteMeta(base + "/"))
+	}
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	b.WriteString(globRegexp(line))
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		// Like git, skip a pattern that cannot match anything sensible
+		return rule{}, false
+	}
+	r.re = re
+	return r, true
+}
+
+// trimTrailingSpaces removes trailing spaces unless they are escaped with
+// a backslash.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// globRegexp translates a glob to a regular expression matching whole
+// slash-separated paths.
+func globRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**") &&
+			(i == 0 || glob[i-1] == '/') && (i+2 == len(glob) || glob[i+2] == '/'):
+			if i+2 == len(glob) {
+				// Trailing /**: everything inside
+				b.WriteString(".*")
+				i++
+			} else {
+				// Leading **/ or /**/: zero or more directories
+				b.WriteString("(?:.*/)?")
+				i += 2
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			class, n := bracketClass(glob[i:])
+			if n == 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(class)
+			i += n - 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// bracketClass translates the bracket expression at the start of s, such
+// as [a-z] or [!0-9], returning it and the number of bytes it spans, or 0
+// if s has no closing bracket.
+func bracketClass(s string) (string, int) {
+	i := 1
+	negate := false
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		negate = true
+		i++
+	}
+	// A ] first in the class is literal
+	start := i
+	for i < len(s) && (s[i] != ']' || i == start) {
+		if s[i] == '\\' {
+			i++
+		}
+		i++
+	}
+	if i >= len(s) {
+		return "", 0
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	if negate {
+		b.WriteString("^")
+	}
+	for j := start; j < i; j++ {
+		switch c := s[j]; c {
+		case '\\':
+			j++
+			b.WriteString(regexp.QuoteMeta(s[j : j+1]))
+		case '[', ']', '^':
+			b.WriteString(`\`)
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if negate {
+		// A negated class never matches a separator
+		b.WriteString("/")
+	}
+	b.WriteString("]")
+	return b.String(), i + 1
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ignore
+
+import "testing"
+
+type matchCase struct {
+	path  string
+	isDir bool
+	want  bool
+}
+
+func checkMatches(t *testing.T, m *Matcher, cases []matchCase) {
+	t.Helper()
+	for _, c := range cases {
+		if got := m.Match(c.path, c.isDir); got != c.want {
+			t.Errorf("Match(%q, isDir %v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+}
+
+func TestMatchPatterns(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		patterns string
+		cases    []matchCase
+	}{
+		{
+			name:     "comments and blank lines",
+			patterns: "# *.go\n\n  \n",
+			cases:    []matchCase{{"main.go", false, false}},
+		},
+		{
+			name:     "unanchored name at any depth",
+			patterns: "*.log\n",
+			cases: []matchCase{
+				{"app.log", false, true},
+				{"logs/deep/app.log", false, true},
+				{"app.log.txt", false, false},
+			},
+		},
+		{
+			name:     "anchored by a leading slash",
+			patterns: "/build\n",
+			cases: []matchCase{
+				{"build", true, true},
+				{"build/out.o", false, true},
+				{"src/build", true, false},
+			},
+		},
+		{
+			name:     "anchored by a middle slash",
+			patterns: "docs/*.md\n",
+			cases: []matchCase{
+				{"docs/a.md", false, true},
+				{"docs/sub/a.md", false, false},
+				{"x/docs/a.md", false, false},
+			},
+		},
+		{
+			name:     "directory only",
+			patterns: "vendor/\n",
+			cases: []matchCase{
+				{"vendor", true, true},
+				{"vendor/lib.go", false, true},
+				{"pkg/vendor/lib.go", false, true},
+				{"vendor", false, false},
+			},
+		},
+		{
+			name:     "leading **",
+			patterns: "**/testdata\n",
+			cases: []matchCase{
+				{"testdata/a.json", false, true},
+				{"a/b/testdata/a.json", false, true},
+			},
+		},
+		{
+			name:     "middle **",
+			patterns: "a/**/z.txt\n",
+			cases: []matchCase{
+				{"a/z.txt", false, true},
+				{"a/b/c/z.txt", false, true},
+				{"b/a/z.txt", false, false},
+			},
+		},
+		{
+			name:     "trailing **",
+			patterns: "gen/**\n",
+			cases: []matchCase{
+				{"gen/a.go", false, true},
+				{"gen/x/y.go", false, true},
+				{"gen", true, false},
+			},
+		},
+		{
+			name:     "? and classes",
+			patterns: "file?.[ch]\n[!a]*.tmp\n",
+			cases: []matchCase{
+				{"file1.c", false, true},
+				{"file1.go", false, false},
+				{"file12.c", false, false},
+				{"b.tmp", false, true},
+				{"a.tmp", false, false},
+			},
+		},
+		{
+			name:     "negation",
+			patterns: "*.golden\n!keep.golden\n",
+			cases: []matchCase{
+				{"x.golden", false, true},
+				{"keep.golden", false, false},
+				{"sub/keep.golden", false, false},
+			},
+		},
+		{
+			name:     "the last matching pattern wins",
+			patterns: "!keep.golden\n*.golden\n",
+			cases:    []matchCase{{"keep.golden", false, true}},
+		},
+		{
+			name:     "no negation inside an ignored directory",
+			patterns: "out/\n!out/keep.txt\n",
+			cases:    []matchCase{{"out/keep.txt", false, true}},
+		},
+		{
+			name:     "escapes",
+			patterns: "\\#notes\n\\!bang\ntrailing\\ \n",
+			cases: []matchCase{
+				{"#notes", false, true},
+				{"!bang", false, true},
+				{"trailing ", false, true},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			checkMatches(t, Compile(tt.patterns), tt.cases)
+		})
+	}
+}
+
+func TestMatchLayers(t *testing.T) {
+	var m Matcher
+	// Shallowest first, as filterIgnored adds them
+	m.Add("", "*.json\n")
+	m.Add("api", "!schema.json\n/local.txt\n")
+	m.Add("api/v2", "schema.json\n")
+
+	checkMatches(t, &m, []matchCase{
+		{"data.json", false, true},
+		{"api/data.json", false, true},
+		// The deeper file takes precedence over the root's
+		{"api/schema.json", false, false},
+		{"api/v1/schema.json", false, false},
+		// And a deeper one still over that
+		{"api/v2/schema.json", false, true},
+		// A file's patterns apply only beneath its directory, anchored there
+		{"api/local.txt", false, true},
+		{"local.txt", false, false},
+		{"api/sub/local.txt", false, false},
+	})
+}
+
+func TestZeroMatcher(t *testing.T) {
+	var m Matcher
+	if m.Match("anything", false) {
+		t.Error("the zero Matcher ignored a path")
+	}
+}
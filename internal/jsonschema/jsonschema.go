@@ -0,0 +1,295 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package jsonschema validates JSON values against the commonly used part
+// of JSON Schema: type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum, maximum, allOf, anyOf, and oneOf. Other keywords are
+// ignored, except $ref, which is rejected rather than silently skipped.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	root any
+}
+
+// Parse parses a JSON Schema document.
+func Parse(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parse JSON schema: %w", err)
+	}
+	switch root.(type) {
+	case map[string]any, bool:
+	default:
+		return nil, fmt.Errorf("JSON schema must be an object")
+	}
+	if err := check(root, "#"); err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+// check reports keywords in a schema, or the schemas nested in it, that
+// cannot be honored.
+func check(schema any, path string) error {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if _, ok := s["$ref"]; ok {
+		return fmt.Errorf("JSON schema: %s: $ref is not supported", path)
+	}
+	if p, ok := s["pattern"].(string); ok {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("JSON schema: %s: invalid pattern: %w", path, err)
+		}
+	}
+
+	if props, ok := s["properties"].(map[string]any); ok {
+		for _, name := range sortedKeys(props) {
+			if err := check(props[name], path+"/properties/"+name); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range []string{"additionalProperties", "items"} {
+		if err := check(s[key], path+"/"+key); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		list, _ := s[key].([]any)
+		for i, sub := range list {
+			if err := check(sub, fmt.Sprintf("%s/%s/%d", path, key, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks v, a value decoded by encoding/json into an any, against
+// the schema. The error names the first part of v that does not conform.
+func (s *Schema) Validate(v any) error {
+	return validate(s.root, v, "$")
+}
+
+func validate(schema, v any, path string) error {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		if b, isBool := schema.(bool); isBool && !b {
+			return fmt.Errorf("%s: no value is allowed here", path)
+		}
+		return nil
+	}
+
+	if t, ok := s["type"]; ok {
+		if err := checkType(t, v, path); err != nil {
+			return err
+		}
+	}
+	if enum, ok := s["enum"].([]any); ok && !contains(enum, v) {
+		return fmt.Errorf("%s: %s is not one of %s", path, encode(v), encode(enum))
+	}
+	if c, ok := s["const"]; ok && !reflect.DeepEqual(c, v) {
+		return fmt.Errorf("%s: %s is not %s", path, encode(v), encode(c))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		if err := validateObject(s, v, path); err != nil {
+			return err
+		}
+	case []any:
+		if err := validateArray(s, v, path); err != nil {
+			return err
+		}
+	case string:
+		n := float64(utf8.RuneCountInString(v))
+		if min, ok := s["minLength"].(float64); ok && n < min {
+			return fmt.Errorf("%s: shorter than %v characters", path, min)
+		}
+		if max, ok := s["maxLength"].(float64); ok && n > max {
+			return fmt.Errorf("%s: longer than %v characters", path, max)
+		}
+		if p, ok := s["pattern"].(string); ok && !regexp.MustCompile(p).MatchString(v) {
+			return fmt.Errorf("%s: %q does not match %q", path, v, p)
+		}
+	case float64:
+		if min, ok := s["minimum"].(float64); ok && v < min {
+			return fmt.Errorf("%s: %v is less than %v", path, v, min)
+		}
+		if max, ok := s["maximum"].(float64); ok && v > max {
+			return fmt.Errorf("%s: %v is greater than %v", path, v, max)
+		}
+	}
+
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			if err := validate(sub, v, path); err != nil {
+				return err
+			}
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok && matches(anyOf, v, path) == 0 {
+		return fmt.Errorf("%s: does not match any of the allowed schemas", path)
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		if n := matches(oneOf, v, path); n != 1 {
+			return fmt.Errorf("%s: matches %d of the schemas instead of exactly one", path, n)
+		}
+	}
+	return nil
+}
+
+func validateObject(s map[string]any, v map[string]any, path string) error {
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, present := v[name]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+	for _, name := range sortedKeys(v) {
+		sub := path + "." + name
+		if schema, ok := props[name]; ok {
+			if err := validate(schema, v[name], sub); err != nil {
+				return err
+			}
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if b, ok := additional.(bool); ok && !b {
+			return fmt.Errorf("%s: property %q is not allowed", path, name)
+		}
+		if err := validate(additional, v[name], sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateArray(s map[string]any, v []any, path string) error {
+	n := float64(len(v))
+	if min, ok := s["minItems"].(float64); ok && n < min {
+		return fmt.Errorf("%s: fewer than %v items", path, min)
+	}
+	if max, ok := s["maxItems"].(float64); ok && n > max {
+		return fmt.Errorf("%s: more than %v items", path, max)
+	}
+	if items, ok := s["items"]; ok {
+		for i, item := range v {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkType checks v against a type keyword, a name or a list of names.
+func checkType(t, v any, path string) error {
+	var names []string
+	switch t := t.(type) {
+	case string:
+		names = []string{t}
+	case []any:
+		for _, n := range t {
+			if s, ok := n.(string); ok {
+				names = append(names, s)
+			}
+		}
+	}
+	for _, name := range names {
+		if hasType(name, v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(names, " or "), typeName(v))
+}
+
+func hasType(name string, v any) bool {
+	switch name {
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	}
+	return typeName(v) == name
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// matches counts the schemas v conforms to.
+func matches(schemas []any, v any, path string) int {
+	n := 0
+	for _, sub := range schemas {
+		if validate(sub, v, path) == nil {
+			n++
+		}
+	}
+	return n
+}
+
+func contains(values []any, v any) bool {
+	for _, e := range values {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func encode(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
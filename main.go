@@ -10,8 +10,5 @@ import (
 )
 
 func main() {
-	root := cmd.NewRootCmd()
-	if err := root.Execute(); err != nil {
-		os.Exit(1)
-	}
+	os.Exit(cmd.Execute())
 }